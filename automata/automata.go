@@ -0,0 +1,166 @@
+// Package automata checks deterministic finite automata for equivalence
+// with the near-linear algorithm of Hopcroft and Karp (1971).
+//
+// Equivalent walks both machines in lockstep from their start states and
+// keeps the pairs of states it has found must be equivalent as the sets of
+// a sparse.DSU over (machine, state) pairs. A pair whose states are already
+// in the same set needs no further exploration, so every union shrinks the
+// number of sets and the walk ends after fewer unions than there are
+// states. Every union joins states that agree on acceptance, so if no
+// explored pair disagrees, the machines accept the same language;
+// otherwise the symbols leading to the disagreeing pair distinguish them.
+package automata
+
+import (
+	"slices"
+
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// DFA is a deterministic finite automaton over symbols of type S, with
+// states numbered [0, n). Transitions may be partial: a missing transition
+// leads to an implicit rejecting dead state.
+type DFA[S comparable] struct {
+	start  int
+	accept []bool
+	next   []map[S]int
+
+	// alphabet lists the symbols of the transitions in order of first use.
+	alphabet []S
+	known    map[S]struct{}
+}
+
+// NewDFA creates a DFA with states [0, states), none accepting and without
+// transitions, that starts in start.
+// Panics if start is out of range.
+func NewDFA[S comparable](states, start int) *DFA[S] {
+	if states < 0 {
+		states = 0
+	}
+	d := &DFA[S]{
+		start:  start,
+		accept: make([]bool, states),
+		next:   make([]map[S]int, states),
+		known:  make(map[S]struct{}),
+	}
+	d.check(start, "NewDFA")
+	return d
+}
+
+// check panics if q is out of range; method names the caller.
+func (d *DFA[S]) check(q int, method string) {
+	if q < 0 || q >= len(d.accept) {
+		panic("automata.DFA: state out of range in " + method)
+	}
+}
+
+// States returns the number of states.
+func (d *DFA[S]) States() int {
+	return len(d.accept)
+}
+
+// SetAccept makes q accepting or not.
+// Panics if q is out of range.
+func (d *DFA[S]) SetAccept(q int, accept bool) {
+	d.check(q, "SetAccept")
+	d.accept[q] = accept
+}
+
+// AddTransition makes the DFA move from from to to on sym, replacing any
+// previous transition from from on sym.
+// Panics if from or to are out of range.
+func (d *DFA[S]) AddTransition(from int, sym S, to int) {
+	d.check(from, "AddTransition")
+	d.check(to, "AddTransition")
+	if d.next[from] == nil {
+		d.next[from] = make(map[S]int)
+	}
+	d.next[from][sym] = to
+	if _, ok := d.known[sym]; !ok {
+		d.known[sym] = struct{}{}
+		d.alphabet = append(d.alphabet, sym)
+	}
+}
+
+// dead is the implicit dead state.
+const dead = -1
+
+// step returns the state reached from q on sym.
+func (d *DFA[S]) step(q int, sym S) int {
+	if q == dead {
+		return dead
+	}
+	if to, ok := d.next[q][sym]; ok {
+		return to
+	}
+	return dead
+}
+
+// accepts reports whether q is accepting.
+func (d *DFA[S]) accepts(q int) bool {
+	return q != dead && d.accept[q]
+}
+
+// Accepts reports whether the DFA accepts word.
+func (d *DFA[S]) Accepts(word []S) bool {
+	q := d.start
+	for _, sym := range word {
+		q = d.step(q, sym)
+	}
+	return d.accepts(q)
+}
+
+// state is a state of one of the two machines compared by Equivalent.
+type state struct {
+	machine int
+	q       int
+}
+
+// Equivalent reports whether a and b accept the same language. If they do
+// not, it also returns a word that exactly one of them accepts; the word is
+// found by breadth-first search and is short, though not necessarily the
+// shortest.
+func Equivalent[S comparable](a, b *DFA[S]) ([]S, bool) {
+	alphabet := slices.Clone(a.alphabet)
+	for _, sym := range b.alphabet {
+		if _, ok := a.known[sym]; !ok {
+			alphabet = append(alphabet, sym)
+		}
+	}
+
+	// pair is an explored pair of states, reached from the pair at index
+	// prev by sym
+	type pair struct {
+		p, q int
+		prev int
+		sym  S
+	}
+	word := func(queue []pair, i int) []S {
+		var w []S
+		for ; i > 0; i = queue[i].prev {
+			w = append(w, queue[i].sym)
+		}
+		slices.Reverse(w)
+		return w
+	}
+
+	dsu := sparse.New[state]()
+	queue := []pair{{p: a.start, q: b.start, prev: -1}}
+	if a.accepts(a.start) != b.accepts(b.start) {
+		return []S{}, false
+	}
+	dsu.Union(state{0, a.start}, state{1, b.start})
+	for i := 0; i < len(queue); i++ {
+		for _, sym := range alphabet {
+			p, q := a.step(queue[i].p, sym), b.step(queue[i].q, sym)
+			if !dsu.Union(state{0, p}, state{1, q}) {
+				continue
+			}
+			queue = append(queue, pair{p: p, q: q, prev: i, sym: sym})
+			if a.accepts(p) != b.accepts(q) {
+				return word(queue, len(queue)-1), false
+			}
+		}
+	}
+	return nil, true
+}
@@ -0,0 +1,27 @@
+package automata
+
+import "fmt"
+
+// ExampleEquivalent compares a machine for "an even number of a's" with one
+// for "an even number of symbols" and finds a word that tells them apart.
+func ExampleEquivalent() {
+	evenA := NewDFA[rune](2, 0)
+	evenA.SetAccept(0, true)
+	evenA.AddTransition(0, 'a', 1)
+	evenA.AddTransition(1, 'a', 0)
+	evenA.AddTransition(0, 'b', 0)
+	evenA.AddTransition(1, 'b', 1)
+
+	evenLen := NewDFA[rune](2, 0)
+	evenLen.SetAccept(0, true)
+	for _, sym := range "ab" {
+		evenLen.AddTransition(0, sym, 1)
+		evenLen.AddTransition(1, sym, 0)
+	}
+
+	w, eq := Equivalent(evenA, evenLen)
+	fmt.Println(eq, string(w), evenA.Accepts(w), evenLen.Accepts(w))
+
+	// Output:
+	// false b true false
+}
@@ -0,0 +1,136 @@
+package automata
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// productEquivalent decides equivalence by exploring every reachable pair
+// of states of the product automaton.
+func productEquivalent(a, b *DFA[byte], alphabet []byte) bool {
+	type pair struct{ p, q int }
+	seen := map[pair]bool{{a.start, b.start}: true}
+	queue := []pair{{a.start, b.start}}
+	for len(queue) > 0 {
+		x := queue[0]
+		queue = queue[1:]
+		if a.accepts(x.p) != b.accepts(x.q) {
+			return false
+		}
+		for _, sym := range alphabet {
+			y := pair{a.step(x.p, sym), b.step(x.q, sym)}
+			if !seen[y] {
+				seen[y] = true
+				queue = append(queue, y)
+			}
+		}
+	}
+	return true
+}
+
+// randomDFA returns a DFA with n states over alphabet and some missing
+// transitions.
+func randomDFA(rng *rand.Rand, n int, alphabet []byte) *DFA[byte] {
+	d := NewDFA[byte](n, rng.Intn(n))
+	for q := 0; q < n; q++ {
+		d.SetAccept(q, rng.Intn(3) == 0)
+		for _, sym := range alphabet {
+			if rng.Intn(6) != 0 {
+				d.AddTransition(q, sym, rng.Intn(n))
+			}
+		}
+	}
+	return d
+}
+
+func TestEquivalentMatchesProduct(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []byte("ab")
+	differ := 0
+	for trial := 0; trial < 2000; trial++ {
+		a := randomDFA(rng, 1+rng.Intn(4), alphabet)
+		b := randomDFA(rng, 1+rng.Intn(4), alphabet)
+		w, eq := Equivalent(a, b)
+		if want := productEquivalent(a, b, alphabet); eq != want {
+			t.Fatalf("trial %d: Equivalent = %v, want %v", trial, eq, want)
+		}
+		if !eq {
+			differ++
+			if a.Accepts(w) == b.Accepts(w) {
+				t.Fatalf("trial %d: %q does not distinguish the machines", trial, w)
+			}
+		}
+		if _, eq := Equivalent(a, a); !eq {
+			t.Fatalf("trial %d: machine differs from itself", trial)
+		}
+	}
+	if differ == 0 || differ == 2000 {
+		t.Fatalf("%d of 2000 pairs differ; the test is not meaningful", differ)
+	}
+}
+
+// multiples returns a DFA with n states that accepts the binary numbers,
+// most significant bit first, whose value is a multiple of k modulo n.
+func multiples(n, k int) *DFA[byte] {
+	d := NewDFA[byte](n, 0)
+	for q := 0; q < n; q++ {
+		d.SetAccept(q, q%k == 0)
+		d.AddTransition(q, '0', 2*q%n)
+		d.AddTransition(q, '1', (2*q+1)%n)
+	}
+	return d
+}
+
+func TestEquivalentNonMinimal(t *testing.T) {
+	// value mod 3 is determined by value mod 6
+	if w, eq := Equivalent(multiples(3, 3), multiples(6, 3)); !eq {
+		t.Fatalf("machines differ on %q", w)
+	}
+	w, eq := Equivalent(multiples(3, 3), multiples(6, 2))
+	if eq || multiples(3, 3).Accepts(w) == multiples(6, 2).Accepts(w) {
+		t.Fatalf("got %q, %v", w, eq)
+	}
+}
+
+func TestEquivalentStartStates(t *testing.T) {
+	a, b := NewDFA[rune](1, 0), NewDFA[rune](1, 0)
+	a.SetAccept(0, true)
+	if w, eq := Equivalent(a, b); eq || len(w) != 0 {
+		t.Fatalf("got %q, %v; want the empty word", w, eq)
+	}
+
+	// a missing transition behaves like one to a rejecting sink
+	c, d := NewDFA[rune](1, 0), NewDFA[rune](2, 0)
+	d.AddTransition(0, 'x', 1)
+	d.AddTransition(1, 'x', 1)
+	if _, eq := Equivalent(c, d); !eq {
+		t.Fatal("partial and complete machines differ")
+	}
+}
+
+func TestEquivalentLarge(t *testing.T) {
+	const n = 50_000
+	// a cycle of n states accepting every 5th state against the same
+	// cycle of 2n states
+	cycle := func(m int) *DFA[byte] {
+		d := NewDFA[byte](m, 0)
+		for q := 0; q < m; q++ {
+			d.SetAccept(q, q%5 == 0)
+			d.AddTransition(q, 'a', (q+1)%m)
+			d.AddTransition(q, 'b', q)
+		}
+		return d
+	}
+	if _, eq := Equivalent(cycle(n), cycle(2*n)); !eq {
+		t.Fatal("cycles differ")
+	}
+}
+
+func TestDFAPanicsOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("AddTransition did not panic")
+		}
+	}()
+	NewDFA[byte](2, 0).AddTransition(0, 'a', 2)
+}
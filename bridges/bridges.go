@@ -0,0 +1,156 @@
+// Package bridges maintains the bridges and the 2-edge-connected components
+// of an undirected graph as edges are added one at a time.
+//
+// It implements the classic online algorithm with two sparse.DSUs, so
+// vertices may be of any comparable type. One DSU tracks the connected
+// components and the other the 2-edge-connected components. Contracting
+// every 2-edge-connected component to a single node turns each connected
+// component into a tree, the bridge tree, whose edges are exactly the
+// bridges. The bridge tree is stored with parent pointers between
+// representatives of 2-edge-connected components:
+//
+//   - An edge between two connected components is a new bridge. The smaller
+//     bridge tree is rerooted at the edge's endpoint and hung below the
+//     other one.
+//   - An edge within a connected component closes a cycle in the bridge
+//     tree. The path between its endpoints is found by climbing from both
+//     ends towards their lowest common ancestor, and the 2-edge-connected
+//     components on it are merged; each edge of the path stops being a
+//     bridge.
+//
+// A sequence of m edge insertions on n vertices takes O(n log n + m α(n))
+// time.
+package bridges
+
+import (
+	"github.com/arunksaha/gdsu/internal/forest"
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// Graph is an undirected graph with incrementally maintained bridges.
+type Graph[T comparable] struct {
+	// conn groups the vertices into connected components and twoEdge into
+	// 2-edge-connected components.
+	conn    *sparse.DSU[T]
+	twoEdge *sparse.DSU[T]
+
+	// size maps the root of every connected component of conn to its
+	// number of vertices; components of one vertex have no entry.
+	size map[T]int
+
+	// tree is the bridge tree: it maps the representative of every
+	// 2-edge-connected component, other than the root of its bridge tree,
+	// to a vertex of its parent component, read through twoEdge, labeled
+	// with the bridge that joins the two.
+	tree *forest.Map[T, [2]T]
+
+	bridges int
+}
+
+// New creates an empty graph.
+func New[T comparable]() *Graph[T] {
+	g := &Graph[T]{
+		conn:    sparse.New[T](),
+		twoEdge: sparse.New[T](),
+		size:    make(map[T]int),
+	}
+	g.tree = forest.NewMap[T, [2]T](g.twoEdge.Find)
+	return g
+}
+
+// sizeOf returns the number of vertices of the connected component whose
+// root in conn is r.
+func (g *Graph[T]) sizeOf(r T) int {
+	if s, ok := g.size[r]; ok {
+		return s
+	}
+	return 1
+}
+
+// AddEdge adds an edge between u and v, adding the vertices if they are
+// new. Self loops are ignored.
+func (g *Graph[T]) AddEdge(u, v T) {
+	a, b := g.twoEdge.Find(u), g.twoEdge.Find(v)
+	if a == b {
+		return
+	}
+	ca, cb := g.conn.Find(u), g.conn.Find(v)
+	if ca == cb {
+		g.mergePath(a, b)
+		return
+	}
+
+	// hang the smaller bridge tree below the larger one
+	if g.sizeOf(ca) > g.sizeOf(cb) {
+		a, b, ca, cb = b, a, cb, ca
+	}
+	g.tree.Link(a, b, [2]T{u, v})
+	g.bridges++
+
+	total := g.sizeOf(ca) + g.sizeOf(cb)
+	delete(g.size, ca)
+	delete(g.size, cb)
+	g.conn.Union(ca, cb)
+	g.size[g.conn.Find(ca)] = total
+}
+
+// mergePath merges the 2-edge-connected components on the bridge tree path
+// between the representatives a and b, which are in the same tree.
+func (g *Graph[T]) mergePath(a, b T) {
+	lca, pathA, pathB := g.tree.Meet(a, b)
+
+	// every component on the paths below the lowest common ancestor is
+	// merged into it, and the bridge above it disappears
+	p, e, hasParent := g.tree.Parent(lca)
+	for _, path := range [][]T{pathA, pathB} {
+		for _, x := range path {
+			g.twoEdge.Union(x, lca)
+			g.tree.Cut(x)
+			g.bridges--
+		}
+	}
+	g.tree.Cut(lca)
+	if r := g.twoEdge.Find(lca); hasParent {
+		g.tree.Set(r, p, e)
+	}
+}
+
+// IsBridge reports whether there is an edge between u and v and it is a
+// bridge, i.e., removing it would disconnect u from v. Vertices that are
+// new are added.
+func (g *Graph[T]) IsBridge(u, v T) bool {
+	a, b := g.twoEdge.Find(u), g.twoEdge.Find(v)
+	if a == b {
+		return false
+	}
+	for _, x := range [2]T{a, b} {
+		if _, e, ok := g.tree.Parent(x); ok && (e == [2]T{u, v} || e == [2]T{v, u}) {
+			return true
+		}
+	}
+	return false
+}
+
+// BridgeCount returns the number of bridges.
+func (g *Graph[T]) BridgeCount() int {
+	return g.bridges
+}
+
+// Bridges returns every bridge, in no particular order, as the pair of
+// vertices it was added with.
+func (g *Graph[T]) Bridges() [][2]T {
+	return g.tree.Labels()
+}
+
+// TwoEdgeConnected reports whether x and y are 2-edge-connected, i.e.,
+// remain connected after the removal of any single edge. Vertices that are
+// new are added.
+func (g *Graph[T]) TwoEdgeConnected(x, y T) bool {
+	return g.twoEdge.Connected(x, y)
+}
+
+// Connected reports whether x and y are connected. Vertices that are new
+// are added.
+func (g *Graph[T]) Connected(x, y T) bool {
+	return g.conn.Connected(x, y)
+}
@@ -0,0 +1,22 @@
+package bridges
+
+import "fmt"
+
+// Example tracks single points of failure as links are added.
+func Example() {
+	g := New[string]()
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("c", "d")
+	fmt.Println(g.BridgeCount(), g.IsBridge("b", "c"))
+
+	// closing a ring removes the bridges on it
+	g.AddEdge("c", "a")
+	fmt.Println(g.BridgeCount(), g.IsBridge("b", "c"), g.IsBridge("c", "d"))
+	fmt.Println(g.TwoEdgeConnected("a", "c"), g.TwoEdgeConnected("a", "d"))
+
+	// Output:
+	// 3 true
+	// 1 false true
+	// true false
+}
@@ -0,0 +1,130 @@
+package bridges
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/arunksaha/gdsu/compact"
+)
+
+// naiveBridges reports, for every edge, whether removing it disconnects its
+// endpoints, and for every pair of vertices whether they stay connected
+// after the removal of any single edge.
+func naiveBridges(n int, edges [][2]int) (bridge []bool, twoEdge [][]bool) {
+	without := func(skip int) *compact.DSU {
+		dsu := compact.New(n)
+		for i, e := range edges {
+			if i != skip {
+				dsu.Union(e[0], e[1])
+			}
+		}
+		return dsu
+	}
+	all := without(-1)
+	twoEdge = make([][]bool, n)
+	for x := range twoEdge {
+		twoEdge[x] = make([]bool, n)
+		for y := range twoEdge[x] {
+			twoEdge[x][y] = all.Connected(x, y)
+		}
+	}
+	bridge = make([]bool, len(edges))
+	for i, e := range edges {
+		dsu := without(i)
+		bridge[i] = !dsu.Connected(e[0], e[1])
+		for x := range twoEdge {
+			for y := range twoEdge[x] {
+				if !dsu.Connected(x, y) {
+					twoEdge[x][y] = false
+				}
+			}
+		}
+	}
+	return bridge, twoEdge
+}
+
+// TestGraphMatchesNaive adds random edges, including parallel edges and
+// self loops, and checks every query after every edge.
+func TestGraphMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		n := 2 + rng.Intn(20)
+		g := New[int]()
+		var edges [][2]int
+		for e := 0; e < 2*n; e++ {
+			u, v := rng.Intn(n), rng.Intn(n)
+			if rng.Intn(3) == 0 {
+				v = (u + 1) % n // favor long paths and cycles
+			}
+			g.AddEdge(u, v)
+			edges = append(edges, [2]int{u, v})
+
+			bridge, twoEdge := naiveBridges(n, edges)
+			count := 0
+			for i, e := range edges {
+				if bridge[i] {
+					count++
+				}
+				if g.IsBridge(e[0], e[1]) != bridge[i] || g.IsBridge(e[1], e[0]) != bridge[i] {
+					t.Fatalf("trial %d: IsBridge%v = %v, want %v", trial, e, !bridge[i], bridge[i])
+				}
+			}
+			if g.BridgeCount() != count || len(g.Bridges()) != count {
+				t.Fatalf("trial %d: BridgeCount() = %d, want %d", trial, g.BridgeCount(), count)
+			}
+			for x := 0; x < n; x++ {
+				for y := 0; y < n; y++ {
+					if g.TwoEdgeConnected(x, y) != twoEdge[x][y] {
+						t.Fatalf("trial %d: TwoEdgeConnected(%d, %d) = %v, want %v", trial, x, y, !twoEdge[x][y], twoEdge[x][y])
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestGraphNetwork checks a small network with a redundant core and a
+// single uplink.
+func TestGraphNetwork(t *testing.T) {
+	g := New[string]()
+	g.AddEdge("core1", "core2")
+	g.AddEdge("core2", "core3")
+	g.AddEdge("core3", "core1")
+	g.AddEdge("core1", "edge")
+	g.AddEdge("edge", "host")
+
+	if g.BridgeCount() != 2 || !g.IsBridge("edge", "core1") || g.IsBridge("core1", "core2") {
+		t.Fatalf("unexpected bridges %v", g.Bridges())
+	}
+	if g.IsBridge("core1", "host") {
+		t.Fatalf("expected no edge between core1 and host")
+	}
+	if !g.TwoEdgeConnected("core2", "core3") || g.TwoEdgeConnected("core1", "edge") {
+		t.Fatalf("unexpected 2-edge-connectivity")
+	}
+
+	// a second uplink makes the edge switch redundant
+	g.AddEdge("edge", "core2")
+	if g.BridgeCount() != 1 || !g.IsBridge("host", "edge") || !g.TwoEdgeConnected("edge", "core3") {
+		t.Fatalf("unexpected bridges %v", g.Bridges())
+	}
+	if !g.Connected("host", "core3") || g.Connected("host", "printer") {
+		t.Fatalf("unexpected connectivity")
+	}
+}
+
+// TestGraphLongPath closes a long path of bridges into one cycle.
+func TestGraphLongPath(t *testing.T) {
+	const n = 100_000
+	g := New[int]()
+	for x := 1; x < n; x++ {
+		g.AddEdge(x, x-1)
+	}
+	if g.BridgeCount() != n-1 {
+		t.Fatalf("expected %d bridges, got %d", n-1, g.BridgeCount())
+	}
+	g.AddEdge(0, n-1)
+	if g.BridgeCount() != 0 || !g.TwoEdgeConnected(0, n/2) {
+		t.Fatalf("expected no bridges, got %d", g.BridgeCount())
+	}
+}
@@ -0,0 +1,268 @@
+// Package cc computes the connected components of static graphs in
+// parallel.
+//
+// Both algorithms work on a compact-style parent slice where
+// parent[i] == i marks a root, hooking roots onto smaller roots with
+// compare-and-swap and shortcutting paths in the style of Shiloach–Vishkin:
+//
+//   - Components processes an edge list directly, hooking the endpoints of
+//     every edge and then shortcutting.
+//   - Afforest (Sutton, Ben-Nun and Barak, 2018) works on a Graph in
+//     compressed sparse row form. It first links a few neighbors of every
+//     node, then samples the nodes to find the largest intermediate
+//     component, and finally skips the remaining edges of every node
+//     already in that component. On graphs with a giant component this
+//     avoids reading most of the edges.
+//
+// Nodes are ints in the range [0, n). Every node is labeled with the
+// minimum node of its component, so the labels are deterministic and
+// agree with the roots of compact.DSU after Canonicalize.
+package cc
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// neighborRounds is the number of neighbors of every node linked
+	// before sampling for the largest component.
+	neighborRounds = 2
+
+	// numSamples is the number of nodes sampled to find the largest
+	// intermediate component.
+	numSamples = 1024
+
+	// grain is the number of nodes a worker claims at a time.
+	grain = 1024
+)
+
+// Components returns the connected-component label of every node in
+// [0, n) for the undirected graph with the given edges, using up to
+// workers goroutines. The label of a node is the minimum node of its
+// component.
+//
+// If workers <= 0, runtime.GOMAXPROCS(0) is used.
+// Panics if n exceeds math.MaxInt32 or any edge has a node out of range.
+func Components(n int, edges [][2]int, workers int) []int {
+	n = checkInput(n, edges, "Components")
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	parent := newParent(n)
+	parallelFor(len(edges), workers, func(i int) {
+		link(parent, int32(edges[i][0]), int32(edges[i][1]))
+	})
+	compress(parent, workers)
+	return labels(parent)
+}
+
+// Afforest returns the connected-component label of every node of g using
+// up to workers goroutines. The label of a node is the minimum node of its
+// component.
+//
+// If workers <= 0, runtime.GOMAXPROCS(0) is used.
+func Afforest(g *Graph, workers int) []int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	n := g.Len()
+	parent := newParent(n)
+
+	// link the first few neighbors of every node
+	for r := 0; r < neighborRounds; r++ {
+		parallelFor(n, workers, func(v int) {
+			if nbrs := g.neighbors(v); r < len(nbrs) {
+				link(parent, int32(v), nbrs[r])
+			}
+		})
+		compress(parent, workers)
+	}
+
+	// link the remaining neighbors, skipping the largest component
+	largest := sampleLargest(parent)
+	parallelFor(n, workers, func(v int) {
+		if atomic.LoadInt32(&parent[v]) == largest {
+			return
+		}
+		nbrs := g.neighbors(v)
+		for r := neighborRounds; r < len(nbrs); r++ {
+			link(parent, int32(v), nbrs[r])
+		}
+	})
+	compress(parent, workers)
+	return labels(parent)
+}
+
+// Graph is an undirected graph over the nodes [0, n) in compressed sparse
+// row form; each edge is stored in both directions.
+type Graph struct {
+	offsets []int
+	targets []int32
+}
+
+// NewGraph builds the compressed sparse row form of the undirected graph
+// with the given edges, using up to workers goroutines.
+//
+// If workers <= 0, runtime.GOMAXPROCS(0) is used.
+// Panics if n exceeds math.MaxInt32 or any edge has a node out of range.
+func NewGraph(n int, edges [][2]int, workers int) *Graph {
+	n = checkInput(n, edges, "NewGraph")
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	degree := make([]int64, n)
+	parallelFor(len(edges), workers, func(i int) {
+		atomic.AddInt64(&degree[edges[i][0]], 1)
+		atomic.AddInt64(&degree[edges[i][1]], 1)
+	})
+
+	offsets := make([]int, n+1)
+	for v, d := range degree {
+		offsets[v+1] = offsets[v] + int(d)
+	}
+
+	// degree now serves as the next free slot of every node
+	for v := range degree {
+		degree[v] = int64(offsets[v])
+	}
+	targets := make([]int32, offsets[n])
+	parallelFor(len(edges), workers, func(i int) {
+		u, v := edges[i][0], edges[i][1]
+		targets[atomic.AddInt64(&degree[u], 1)-1] = int32(v)
+		targets[atomic.AddInt64(&degree[v], 1)-1] = int32(u)
+	})
+	return &Graph{offsets: offsets, targets: targets}
+}
+
+// Len returns the number of nodes.
+func (g *Graph) Len() int {
+	return len(g.offsets) - 1
+}
+
+// neighbors returns the neighbors of v.
+func (g *Graph) neighbors(v int) []int32 {
+	return g.targets[g.offsets[v]:g.offsets[v+1]]
+}
+
+// checkInput validates n and the edges, returning n clamped to be
+// non-negative; fn names the caller in panic messages.
+func checkInput(n int, edges [][2]int, fn string) int {
+	if n < 0 {
+		n = 0
+	}
+	if n > math.MaxInt32 {
+		panic("cc: too many nodes in " + fn)
+	}
+	for _, e := range edges {
+		if e[0] < 0 || e[0] >= n || e[1] < 0 || e[1] >= n {
+			panic("cc: node out of range in " + fn)
+		}
+	}
+	return n
+}
+
+// newParent returns a parent slice of n singleton roots.
+func newParent(n int) []int32 {
+	parent := make([]int32, n)
+	for i := range parent {
+		parent[i] = int32(i)
+	}
+	return parent
+}
+
+// labels converts a fully compressed parent slice to labels.
+func labels(parent []int32) []int {
+	out := make([]int, len(parent))
+	for v, p := range parent {
+		out[v] = int(p)
+	}
+	return out
+}
+
+// link merges the trees containing u and v by hooking the larger of the
+// two roots onto the smaller one.
+func link(parent []int32, u, v int32) {
+	p1 := atomic.LoadInt32(&parent[u])
+	p2 := atomic.LoadInt32(&parent[v])
+	for p1 != p2 {
+		high, low := max(p1, p2), min(p1, p2)
+		pHigh := atomic.LoadInt32(&parent[high])
+		if pHigh == low {
+			return
+		}
+		if pHigh == high && atomic.CompareAndSwapInt32(&parent[high], high, low) {
+			return
+		}
+		p1 = atomic.LoadInt32(&parent[atomic.LoadInt32(&parent[high])])
+		p2 = atomic.LoadInt32(&parent[low])
+	}
+}
+
+// compress points every node directly at its root.
+func compress(parent []int32, workers int) {
+	parallelFor(len(parent), workers, func(v int) {
+		for {
+			p := atomic.LoadInt32(&parent[v])
+			pp := atomic.LoadInt32(&parent[p])
+			if p == pp {
+				return
+			}
+			atomic.StoreInt32(&parent[v], pp)
+		}
+	})
+}
+
+// sampleLargest returns the most frequent root among a fixed-seed random
+// sample of nodes. The choice only affects speed, never the labels.
+func sampleLargest(parent []int32) int32 {
+	if len(parent) == 0 {
+		return -1
+	}
+	rng := rand.New(rand.NewSource(1))
+	counts := make(map[int32]int, numSamples)
+	largest, best := int32(-1), 0
+	for i := 0; i < numSamples; i++ {
+		p := parent[rng.Intn(len(parent))]
+		counts[p]++
+		if counts[p] > best {
+			largest, best = p, counts[p]
+		}
+	}
+	return largest
+}
+
+// parallelFor calls fn(i) for every i in [0, n) using up to workers
+// goroutines, which claim grain-sized chunks on demand.
+func parallelFor(n, workers int, fn func(i int)) {
+	workers = min(workers, (n+grain-1)/grain)
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				lo := int(next.Add(grain)) - grain
+				if lo >= n {
+					return
+				}
+				for i := lo; i < min(lo+grain, n); i++ {
+					fn(i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
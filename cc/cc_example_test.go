@@ -0,0 +1,24 @@
+package cc
+
+import "fmt"
+
+// ExampleComponents labels every node with the minimum node of its component.
+func ExampleComponents() {
+	edges := [][2]int{{4, 2}, {2, 5}, {1, 3}}
+
+	fmt.Println(Components(6, edges, 0))
+
+	// Output:
+	// [0 1 2 1 2 2]
+}
+
+// ExampleAfforest labels the components of a graph built once and kept
+// in compressed sparse row form.
+func ExampleAfforest() {
+	g := NewGraph(6, [][2]int{{4, 2}, {2, 5}, {1, 3}}, 0)
+
+	fmt.Println(Afforest(g, 0))
+
+	// Output:
+	// [0 1 2 1 2 2]
+}
@@ -0,0 +1,84 @@
+package cc
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/arunksaha/gdsu/compact"
+)
+
+// randomEdges returns m random edges over [0, n) from a fixed seed.
+func randomEdges(n, m int, seed int64) [][2]int {
+	rng := rand.New(rand.NewSource(seed))
+	edges := make([][2]int, m)
+	for i := range edges {
+		edges[i] = [2]int{rng.Intn(n), rng.Intn(n)}
+	}
+	return edges
+}
+
+// dsuLabels computes the expected labels with a sequential compact.DSU.
+func dsuLabels(n int, edges [][2]int) []int {
+	dsu := compact.New(n)
+	dsu.UnionPairs(edges)
+	dsu.Canonicalize()
+	labels := make([]int, n)
+	for v := range labels {
+		labels[v] = dsu.Find(v)
+	}
+	return labels
+}
+
+// TestMatchesDSU cross-checks Components and Afforest against compact.DSU
+// on random graphs ranging from mostly isolated nodes to one giant component.
+func TestMatchesDSU(t *testing.T) {
+	const n = 5000
+	for _, m := range []int{0, n / 4, n / 2, n, 4 * n} {
+		edges := randomEdges(n, m, int64(m))
+		want := dsuLabels(n, edges)
+		for _, workers := range []int{1, 4, 0} {
+			if got := Components(n, edges, workers); !slices.Equal(got, want) {
+				t.Fatalf("m=%d workers=%d: Components labels differ from compact.DSU", m, workers)
+			}
+			g := NewGraph(n, edges, workers)
+			if got := Afforest(g, workers); !slices.Equal(got, want) {
+				t.Fatalf("m=%d workers=%d: Afforest labels differ from compact.DSU", m, workers)
+			}
+		}
+	}
+}
+
+// TestStructured covers self loops, duplicate edges, a path whose
+// minimum is at the far end, and a star.
+func TestStructured(t *testing.T) {
+	edges := [][2]int{{3, 3}, {9, 8}, {8, 7}, {7, 6}, {9, 8}, {0, 2}, {0, 4}, {0, 5}}
+	want := []int{0, 1, 0, 3, 0, 0, 6, 6, 6, 6}
+
+	if got := Components(10, edges, 2); !slices.Equal(got, want) {
+		t.Fatalf("Components = %v, want %v", got, want)
+	}
+	if got := Afforest(NewGraph(10, edges, 2), 2); !slices.Equal(got, want) {
+		t.Fatalf("Afforest = %v, want %v", got, want)
+	}
+	if got := Afforest(NewGraph(0, nil, 2), 2); len(got) != 0 {
+		t.Fatalf("expected no labels, got %v", got)
+	}
+}
+
+// TestOutOfRange ensures Components and NewGraph panic on edges outside [0, n).
+func TestOutOfRange(t *testing.T) {
+	for name, build := range map[string]func(){
+		"Components": func() { Components(3, [][2]int{{0, 3}}, 1) },
+		"NewGraph":   func() { NewGraph(3, [][2]int{{-1, 0}}, 1) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("expected panic on out-of-range edge in %s, got none", name)
+				}
+			}()
+			build()
+		}()
+	}
+}
@@ -3,26 +3,48 @@
 // identifiers.
 //
 // The compact DSU requires a fixed capacity at initialization and stores
-// parent and rank information in contiguous slices, making it suitable
+// parent and rank information in contiguous slice pages, making it suitable
 // for performance-critical workloads such as graph algorithms involving
 // nodes indexed 0..n-1 where memory locality and speed are important.
 package compact
 
-import "github.com/arunksaha/gdsu"
+import (
+	"slices"
+
+	"github.com/arunksaha/gdsu"
+)
+
+// Storage is split into fixed-size pages so that Fork can share unchanged
+// pages between DSUs and copy only the pages that are written to.
+const (
+	pageShift = 10
+	pageSize  = 1 << pageShift
+	pageMask  = pageSize - 1
+)
 
 // DSU is an int-based, slice-backed Disjoint-Set Union implementation.
 //
 // It manages integers in the fixed range [0, n). It is compact and fast
 // but not sparse: you must choose the capacity at construction time.
 type DSU struct {
-	// parent[i] stores the parent of element i;
-	// if parent[i] == i, then i is the root of its set.
-	// The length of the slice is fixed at initialization.
-	parent []int
+	// parent[p][i] stores the parent of element p*pageSize+i;
+	// if the parent of x is x, then x is the root of its set.
+	// The number of elements is fixed at initialization.
+	parent [][]int
+
+	// rank[p][i] stores an upper bound on the height of the tree rooted at
+	// element p*pageSize+i. Used for union-by-rank to maintain small tree
+	// depth and high performance.
+	rank [][]int
 
-	// rank[i] stores an upper bound on the height of the tree rooted at i.
-	// Used for union-by-rank to maintain small tree depth and high performance.
-	rank []int
+	// parentShared[p] and rankShared[p] report whether the corresponding
+	// page may be referenced by another DSU created through Fork. Shared
+	// pages are copied before they are written to.
+	parentShared []bool
+	rankShared   []bool
+
+	// size is the number of elements, i.e., the range is [0, size).
+	size int
 }
 
 // New creates a DSU for elements in the range [0, size).
@@ -30,21 +52,87 @@ func New(size int) *DSU {
 	if size < 0 {
 		size = 0
 	}
-	parent := make([]int, size)
-	rank := make([]int, size)
-	for i := 0; i < size; i++ {
-		parent[i] = i
-		rank[i] = 0
+	numPages := (size + pageSize - 1) >> pageShift
+	parent := make([][]int, numPages)
+	rank := make([][]int, numPages)
+	for p := 0; p < numPages; p++ {
+		base := p << pageShift
+		n := min(pageSize, size-base)
+		parent[p] = make([]int, n)
+		rank[p] = make([]int, n)
+		for i := 0; i < n; i++ {
+			parent[p][i] = base + i
+		}
 	}
 	return &DSU{
-		parent: parent,
-		rank:   rank,
+		parent:       parent,
+		rank:         rank,
+		parentShared: make([]bool, numPages),
+		rankShared:   make([]bool, numPages),
+		size:         size,
+	}
+}
+
+// Fork returns an independent copy of the DSU in O(n / pageSize) time.
+//
+// The fork and the original share all pages until either of them writes
+// to a page, at which point the writer takes a private copy of just that
+// page. Both may then evolve separately; neither observes the other's
+// subsequent unions. Forks of forks are supported.
+func (dsu *DSU) Fork() *DSU {
+	numPages := len(dsu.parent)
+	fork := &DSU{
+		parent:       append([][]int(nil), dsu.parent...),
+		rank:         append([][]int(nil), dsu.rank...),
+		parentShared: make([]bool, numPages),
+		rankShared:   make([]bool, numPages),
+		size:         dsu.size,
+	}
+	for p := 0; p < numPages; p++ {
+		dsu.parentShared[p], dsu.rankShared[p] = true, true
+		fork.parentShared[p], fork.rankShared[p] = true, true
+	}
+	return fork
+}
+
+// parentOf returns the parent of x.
+func (dsu *DSU) parentOf(x int) int {
+	return dsu.parent[x>>pageShift][x&pageMask]
+}
+
+// setParent sets the parent of x to p, copying a shared page first.
+func (dsu *DSU) setParent(x, p int) {
+	pg := x >> pageShift
+	if dsu.parentShared[pg] {
+		dsu.parent[pg] = append([]int(nil), dsu.parent[pg]...)
+		dsu.parentShared[pg] = false
 	}
+	dsu.parent[pg][x&pageMask] = p
 }
 
-// boundsCheck ensures x is within [0, len(parent)).
+// rankOf returns the rank of x.
+func (dsu *DSU) rankOf(x int) int {
+	return dsu.rank[x>>pageShift][x&pageMask]
+}
+
+// setRank sets the rank of x to r, copying a shared page first.
+func (dsu *DSU) setRank(x, r int) {
+	pg := x >> pageShift
+	if dsu.rankShared[pg] {
+		dsu.rank[pg] = append([]int(nil), dsu.rank[pg]...)
+		dsu.rankShared[pg] = false
+	}
+	dsu.rank[pg][x&pageMask] = r
+}
+
+// Len returns the number of elements, i.e., the range is [0, Len()).
+func (dsu *DSU) Len() int {
+	return dsu.size
+}
+
+// boundsCheck ensures x is within [0, size).
 func (dsu *DSU) boundsCheck(x int) bool {
-	return 0 <= x && x < len(dsu.parent)
+	return 0 <= x && x < dsu.size
 }
 
 // Find returns the representative element (root) of the set containing x.
@@ -53,17 +141,24 @@ func (dsu *DSU) Find(x int) int {
 	if !dsu.boundsCheck(x) {
 		panic("compact.DSU: index out of range in Find")
 	}
+	return dsu.find(x)
+}
 
+// find is Find without the bounds check.
+func (dsu *DSU) find(x int) int {
 	root := x
 	// first walk to root
-	for dsu.parent[root] != root {
-		root = dsu.parent[root]
+	for p := dsu.parentOf(root); p != root; p = dsu.parentOf(root) {
+		root = p
 	}
 
-	// compress
+	// compress; direct children of root are left untouched so that
+	// shared pages are not copied needlessly
 	for x != root {
-		p := dsu.parent[x]
-		dsu.parent[x] = root
+		p := dsu.parentOf(x)
+		if p != root {
+			dsu.setParent(x, root)
+		}
 		x = p
 	}
 
@@ -77,16 +172,22 @@ func (dsu *DSU) Union(x, y int) bool {
 	if !dsu.boundsCheck(x) || !dsu.boundsCheck(y) {
 		panic("compact.DSU: index out of range in Union")
 	}
-	rootX, rootY := dsu.Find(x), dsu.Find(y)
+	return dsu.union(x, y)
+}
+
+// union is Union without the bounds check.
+func (dsu *DSU) union(x, y int) bool {
+	rootX, rootY := dsu.find(x), dsu.find(y)
 	if rootX == rootY {
 		return false
 	}
-	if dsu.rank[rootX] < dsu.rank[rootY] {
+	rankX, rankY := dsu.rankOf(rootX), dsu.rankOf(rootY)
+	if rankX < rankY {
 		rootX, rootY = rootY, rootX
 	}
-	dsu.parent[rootY] = rootX
-	if dsu.rank[rootX] == dsu.rank[rootY] {
-		dsu.rank[rootX]++
+	dsu.setParent(rootY, rootX)
+	if rankX == rankY {
+		dsu.setRank(rootX, rankX+1)
 	}
 	return true
 }
@@ -97,18 +198,118 @@ func (dsu *DSU) Connected(x, y int) bool {
 	if !dsu.boundsCheck(x) || !dsu.boundsCheck(y) {
 		panic("compact.DSU: index out of range in Connected")
 	}
-	return dsu.Find(x) == dsu.Find(y)
+	return dsu.find(x) == dsu.find(y)
+}
+
+// UnionAll merges the sets containing all of xs into one set.
+// Returns the number of merges that occurred.
+// Panics if any element is out of range.
+func (dsu *DSU) UnionAll(xs ...int) int {
+	for _, x := range xs {
+		if !dsu.boundsCheck(x) {
+			panic("compact.DSU: index out of range in UnionAll")
+		}
+	}
+	merges := 0
+	for i := 1; i < len(xs); i++ {
+		if dsu.union(xs[0], xs[i]) {
+			merges++
+		}
+	}
+	return merges
+}
+
+// UnionPairs merges the sets containing each pair of elements.
+// Returns the number of merges that occurred.
+// Panics if any element is out of range.
+func (dsu *DSU) UnionPairs(pairs [][2]int) int {
+	for _, p := range pairs {
+		if !dsu.boundsCheck(p[0]) || !dsu.boundsCheck(p[1]) {
+			panic("compact.DSU: index out of range in UnionPairs")
+		}
+	}
+	merges := 0
+	for _, p := range pairs {
+		if dsu.union(p[0], p[1]) {
+			merges++
+		}
+	}
+	return merges
+}
+
+// findManyLanes is the number of independent Find walks FindMany
+// interleaves, which lets the memory accesses of different elements
+// overlap instead of waiting on each other.
+const findManyLanes = 16
+
+// FindMany appends the representative of each element of xs to dst and
+// returns the extended slice; the i-th appended value is the root of xs[i].
+//
+// The elements are processed in interleaved groups rather than one after
+// another, which hides memory latency when xs is scattered over a large
+// DSU. Panics if any element is out of range.
+func (dsu *DSU) FindMany(dst, xs []int) []int {
+	for _, x := range xs {
+		if !dsu.boundsCheck(x) {
+			panic("compact.DSU: index out of range in FindMany")
+		}
+	}
+	start := len(dst)
+	dst = slices.Grow(dst, len(xs))[:start+len(xs)]
+	out := dst[start:]
+
+	for base := 0; base < len(xs); base += findManyLanes {
+		group := xs[base:min(base+findManyLanes, len(xs))]
+		roots := out[base : base+len(group)]
+		copy(roots, group)
+		// advance every lane by one step per round until all reach a root
+		for moved := true; moved; {
+			moved = false
+			for j, r := range roots {
+				if p := dsu.parentOf(r); p != r {
+					roots[j] = p
+					moved = true
+				}
+			}
+		}
+		// compress, as Find does
+		for j, x := range group {
+			for root := roots[j]; x != root; {
+				p := dsu.parentOf(x)
+				if p != root {
+					dsu.setParent(x, root)
+				}
+				x = p
+			}
+		}
+	}
+	return dst
+}
+
+// ConnectedMany appends to dst whether the elements of each pair are in the
+// same set and returns the extended slice.
+// Panics if any element is out of range.
+func (dsu *DSU) ConnectedMany(dst []bool, pairs [][2]int) []bool {
+	for _, p := range pairs {
+		if !dsu.boundsCheck(p[0]) || !dsu.boundsCheck(p[1]) {
+			panic("compact.DSU: index out of range in ConnectedMany")
+		}
+	}
+	for _, p := range pairs {
+		dst = append(dst, dsu.find(p[0]) == dsu.find(p[1]))
+	}
+	return dst
 }
 
 // Groups returns a map from root -> slice of elements in that set.
 func (dsu *DSU) Groups() map[int][]int {
 	groups := make(map[int][]int)
-	for x := range dsu.parent {
+	for x := 0; x < dsu.size; x++ {
 		root := dsu.Find(x)
 		groups[root] = append(groups[root], x)
 	}
 	return groups
 }
 
-// Compile-time assertion that DSU implements gdsu.DSU[int].
-var _ gdsu.DSU[int] = (*DSU)(nil)
+// Compile-time assertion that DSU implements gdsu.BatchDSU[int].
+var _ gdsu.BatchDSU[int] = (*DSU)(nil)
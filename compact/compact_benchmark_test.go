@@ -86,3 +86,51 @@ func BenchmarkCompactWorstCase(b *testing.B) {
 		}
 	}
 }
+
+// BenchmarkCompactFork measures forking a populated DSU and applying a
+// small speculative batch of unions to the fork.
+func BenchmarkCompactFork(b *testing.B) {
+	b.ReportAllocs()
+	dsu := New(NumElements)
+	for i := 0; i < NumElements-1; i += 2 {
+		dsu.Union(i, i+1)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		fork := dsu.Fork()
+		for i := 0; i < 50; i++ {
+			fork.Union(rand.Intn(NumElements), rand.Intn(NumElements))
+		}
+	}
+}
+
+// BenchmarkCompactFindMany compares a loop of Find() calls with a single
+// FindMany() call on a batch of random elements from a large DSU.
+func BenchmarkCompactFindMany(b *testing.B) {
+	const size = 1 << 24
+	dsu := New(size)
+	for i := 0; i < size; i++ {
+		dsu.Union(rand.Intn(size), rand.Intn(size))
+	}
+	xs := make([]int, 1<<16)
+	for i := range xs {
+		xs[i] = rand.Intn(size)
+	}
+	dst := make([]int, 0, len(xs))
+
+	b.Run("Loop", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			dst = dst[:0]
+			for _, x := range xs {
+				dst = append(dst, dsu.Find(x))
+			}
+		}
+	})
+
+	b.Run("Batch", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			dst = dsu.FindMany(dst[:0], xs)
+		}
+	})
+}
@@ -129,3 +129,51 @@ func ExampleDSU_Groups() {
 	// 3,9,27,81
 	// 5,25
 }
+
+// ExampleDSU_Fork illustrates evaluating a speculative scenario on a fork
+// without affecting the original.
+func ExampleDSU_Fork() {
+	dsu := New(4)
+	dsu.Union(0, 1)
+
+	scenario := dsu.Fork()
+	scenario.Union(1, 2)
+
+	fmt.Println(scenario.Connected(0, 2)) // merged in the scenario
+	fmt.Println(dsu.Connected(0, 2))      // original unchanged
+
+	// Output:
+	// true
+	// false
+}
+
+// ExampleNewParallel illustrates bulk loading edges with several goroutines;
+// every set is rooted at its minimum element regardless of scheduling.
+func ExampleNewParallel() {
+	edges := [][2]int{{5, 3}, {3, 4}, {2, 1}}
+	dsu := NewParallel(6, edges, 4)
+
+	fmt.Println(dsu.Find(5), dsu.Find(4), dsu.Find(2))
+
+	// Output:
+	// 3 3 1
+}
+
+// ExampleWitness_Path illustrates explaining a connection with the labeled
+// unions that established it.
+func ExampleWitness_Path() {
+	w := NewWitness[string](5)
+	w.UnionWith(0, 1, "cable A")
+	w.UnionWith(2, 1, "cable B")
+	w.UnionWith(3, 4, "cable C")
+
+	path, ok := w.Path(0, 2)
+	fmt.Println(ok, path)
+
+	_, ok = w.Path(0, 4)
+	fmt.Println(ok)
+
+	// Output:
+	// true [{0 1 cable A} {2 1 cable B}]
+	// false
+}
@@ -45,6 +45,17 @@ func TestCompactNewNegativeSize(t *testing.T) {
 	_ = New(-5)
 }
 
+// TestCompactLen ensures Len() reports the size given to New, spanning
+// several pages, and 0 for a negative size.
+func TestCompactLen(t *testing.T) {
+	if dsu := New(-5); dsu.Len() != 0 {
+		t.Fatalf("Len() = %d, want 0", dsu.Len())
+	}
+	if dsu := New(3000); dsu.Len() != 3000 {
+		t.Fatalf("Len() = %d, want 3000", dsu.Len())
+	}
+}
+
 // TestFindOutOfBounds ensures Find() panics when index is outside the valid range.
 func TestCompactFindOutOfBounds(t *testing.T) {
 	dsu := New(5)
@@ -123,3 +134,162 @@ func TestCompactImplementsInterface(t *testing.T) {
 	// Compile-time interface conformance check.
 	var _ gdsu.DSU[int] = (*DSU)(nil)
 }
+
+// TestCompactForkIndependent ensures a fork and its original evolve separately.
+func TestCompactForkIndependent(t *testing.T) {
+	dsu := New(3 * pageSize)
+	dsu.Union(0, 1)
+
+	fork := dsu.Fork()
+	if !fork.Connected(0, 1) {
+		t.Fatal("fork should inherit existing unions")
+	}
+
+	fork.Union(1, 2*pageSize+5)
+	dsu.Union(2, 3)
+
+	if dsu.Connected(0, 2*pageSize+5) {
+		t.Fatal("union in fork leaked into the original")
+	}
+	if fork.Connected(2, 3) {
+		t.Fatal("union in original leaked into the fork")
+	}
+	if !fork.Connected(0, 2*pageSize+5) || !dsu.Connected(2, 3) {
+		t.Fatal("unions after fork were lost")
+	}
+}
+
+// TestCompactForkSharesPages ensures only the written pages are copied.
+func TestCompactForkSharesPages(t *testing.T) {
+	dsu := New(4 * pageSize)
+	fork := dsu.Fork()
+
+	fork.Union(pageSize, pageSize+1)
+
+	if &fork.parent[0][0] != &dsu.parent[0][0] {
+		t.Fatal("untouched page 0 should still be shared")
+	}
+	if &fork.parent[1][0] == &dsu.parent[1][0] {
+		t.Fatal("written page 1 should have been copied")
+	}
+	if &fork.parent[3][0] != &dsu.parent[3][0] {
+		t.Fatal("untouched page 3 should still be shared")
+	}
+}
+
+// TestCompactForkOfFork ensures chains of forks stay independent.
+func TestCompactForkOfFork(t *testing.T) {
+	dsu := New(10)
+	dsu.Union(0, 1)
+	a := dsu.Fork()
+	a.Union(1, 2)
+	b := a.Fork()
+	b.Union(2, 3)
+	a.Union(4, 5)
+
+	if dsu.Connected(1, 2) || a.Connected(2, 3) || b.Connected(4, 5) {
+		t.Fatal("forks should not observe each other's later unions")
+	}
+	if !b.Connected(0, 3) || !a.Connected(0, 2) || !a.Connected(4, 5) {
+		t.Fatal("forks lost their own unions")
+	}
+	if len(dsu.Groups()) != 9 || len(a.Groups()) != 7 || len(b.Groups()) != 7 {
+		t.Fatalf("unexpected group counts %d, %d, %d",
+			len(dsu.Groups()), len(a.Groups()), len(b.Groups()))
+	}
+}
+
+// TestCompactUnionAll ensures UnionAll merges a whole group and counts merges.
+func TestCompactUnionAll(t *testing.T) {
+	dsu := New(6)
+	dsu.Union(1, 2)
+
+	if merges := dsu.UnionAll(0, 1, 2, 3); merges != 2 {
+		t.Fatalf("expected 2 merges, got %d", merges)
+	}
+	if !dsu.Connected(0, 3) || dsu.Connected(0, 4) {
+		t.Fatal("UnionAll merged the wrong elements")
+	}
+	if merges := dsu.UnionAll(); merges != 0 {
+		t.Fatalf("expected 0 merges for an empty group, got %d", merges)
+	}
+}
+
+// TestCompactUnionPairs ensures UnionPairs counts only actual merges.
+func TestCompactUnionPairs(t *testing.T) {
+	dsu := New(5)
+	pairs := [][2]int{{0, 1}, {1, 2}, {0, 2}, {3, 4}, {4, 3}}
+
+	if merges := dsu.UnionPairs(pairs); merges != 3 {
+		t.Fatalf("expected 3 merges, got %d", merges)
+	}
+	if len(dsu.Groups()) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(dsu.Groups()))
+	}
+}
+
+// TestCompactFindMany ensures FindMany agrees with Find for small and
+// reordered batches and appends to dst.
+func TestCompactFindMany(t *testing.T) {
+	const size = 8 * pageSize
+	dsu := New(size)
+	for i := 0; i+7 < size; i += 7 {
+		dsu.Union(i, i+7)
+	}
+
+	for _, n := range []int{10, 3*findManyLanes + 5} {
+		xs := make([]int, n)
+		for i := range xs {
+			xs[i] = (i * 7919) % size
+		}
+		dst := []int{-1}
+		dst = dsu.FindMany(dst, xs)
+		if len(dst) != n+1 || dst[0] != -1 {
+			t.Fatalf("FindMany did not append to dst: len %d", len(dst))
+		}
+		for i, x := range xs {
+			if dst[i+1] != dsu.Find(x) {
+				t.Fatalf("FindMany(%d) = %d, want %d", x, dst[i+1], dsu.Find(x))
+			}
+		}
+	}
+}
+
+// TestCompactConnectedMany ensures ConnectedMany agrees with Connected.
+func TestCompactConnectedMany(t *testing.T) {
+	dsu := New(4)
+	dsu.Union(0, 1)
+
+	got := dsu.ConnectedMany(nil, [][2]int{{0, 1}, {1, 2}, {3, 3}})
+	want := []bool{true, false, true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ConnectedMany[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestCompactBatchOutOfBounds ensures batch operations panic on out-of-range
+// elements before modifying the DSU.
+func TestCompactBatchOutOfBounds(t *testing.T) {
+	calls := map[string]func(dsu *DSU){
+		"UnionAll":      func(dsu *DSU) { dsu.UnionAll(0, 1, 9) },
+		"UnionPairs":    func(dsu *DSU) { dsu.UnionPairs([][2]int{{0, 1}, {2, -1}}) },
+		"FindMany":      func(dsu *DSU) { dsu.FindMany(nil, []int{0, 5}) },
+		"ConnectedMany": func(dsu *DSU) { dsu.ConnectedMany(nil, [][2]int{{7, 0}}) },
+	}
+	for name, call := range calls {
+		dsu := New(5)
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("expected panic on out-of-range %s(), got none", name)
+				}
+			}()
+			call(dsu)
+		}()
+		if dsu.Connected(0, 1) {
+			t.Fatalf("%s modified the DSU before panicking", name)
+		}
+	}
+}
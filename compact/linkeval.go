@@ -0,0 +1,112 @@
+package compact
+
+// LinkEval is an int-based, slice-backed link-eval forest over the fixed
+// range [0, n).
+//
+// Like DSU, it maintains a forest with path compression, but instead of
+// merging sets it evaluates paths: every element carries an integer key,
+// and Eval(v) returns the element with the minimum key on the path from v
+// up to, but excluding, the root of its tree. It is the core of the
+// Lengauer–Tarjan dominator algorithm. This is the simple variant with
+// path compression only, so a sequence of m operations takes
+// O(m log n) time.
+type LinkEval struct {
+	// ancestor[v] is the parent of v in the compressed forest, or -1 if v
+	// is a root.
+	ancestor []int
+
+	// label[v] is the element with the minimum key on the path from v up
+	// to, but excluding, ancestor[v].
+	label []int
+
+	// key[v] is the key of v.
+	key []int
+
+	// path is scratch space for Eval.
+	path []int
+}
+
+// NewLinkEval creates a link-eval forest of singleton trees for elements
+// in the range [0, size). The key of every element is initially itself.
+func NewLinkEval(size int) *LinkEval {
+	if size < 0 {
+		size = 0
+	}
+	f := &LinkEval{
+		ancestor: make([]int, size),
+		label:    make([]int, size),
+		key:      make([]int, size),
+	}
+	for v := 0; v < size; v++ {
+		f.ancestor[v] = -1
+		f.label[v] = v
+		f.key[v] = v
+	}
+	return f
+}
+
+// boundsCheck ensures v is within [0, len(ancestor)).
+func (f *LinkEval) boundsCheck(v int) bool {
+	return 0 <= v && v < len(f.ancestor)
+}
+
+// Key returns the key of v.
+// Panics if v is out of range.
+func (f *LinkEval) Key(v int) int {
+	if !f.boundsCheck(v) {
+		panic("compact.LinkEval: index out of range in Key")
+	}
+	return f.key[v]
+}
+
+// SetKey sets the key of v. The key must not change once v has been
+// linked below another element, since compressed paths cache minimums.
+// Panics if v is out of range.
+func (f *LinkEval) SetKey(v, k int) {
+	if !f.boundsCheck(v) {
+		panic("compact.LinkEval: index out of range in SetKey")
+	}
+	f.key[v] = k
+}
+
+// Link makes parent the parent of child, which must be the root of its
+// tree. Panics if parent or child are out of range or child is not a root.
+func (f *LinkEval) Link(parent, child int) {
+	if !f.boundsCheck(parent) || !f.boundsCheck(child) {
+		panic("compact.LinkEval: index out of range in Link")
+	}
+	if f.ancestor[child] != -1 {
+		panic("compact.LinkEval: child is not a root in Link")
+	}
+	f.ancestor[child] = parent
+}
+
+// Eval returns v if v is a root, and otherwise the element with the
+// minimum key on the path from v up to, but excluding, the root of its
+// tree. Panics if v is out of range.
+func (f *LinkEval) Eval(v int) int {
+	if !f.boundsCheck(v) {
+		panic("compact.LinkEval: index out of range in Eval")
+	}
+	if f.ancestor[v] == -1 {
+		return v
+	}
+
+	// collect the elements whose ancestor is not a root ...
+	path := f.path[:0]
+	for x := v; f.ancestor[f.ancestor[x]] != -1; x = f.ancestor[x] {
+		path = append(path, x)
+	}
+	// ... and compress them from the top down, carrying minimums along
+	for i := len(path) - 1; i >= 0; i-- {
+		x := path[i]
+		a := f.ancestor[x]
+		if f.key[f.label[a]] < f.key[f.label[x]] {
+			f.label[x] = f.label[a]
+		}
+		f.ancestor[x] = f.ancestor[a]
+	}
+	f.path = path
+
+	return f.label[v]
+}
@@ -0,0 +1,95 @@
+package compact
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// naiveEval walks uncompressed parent pointers to evaluate v.
+func naiveEval(parent, key []int, v int) int {
+	if parent[v] == -1 {
+		return v
+	}
+	best := v
+	for x := v; parent[x] != -1; x = parent[x] {
+		if key[x] < key[best] {
+			best = x
+		}
+	}
+	return best
+}
+
+// TestLinkEvalMatchesNaive cross-checks random link and eval sequences,
+// with keys set before linking as Lengauer–Tarjan does.
+func TestLinkEvalMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 300
+	f := NewLinkEval(n)
+	parent := make([]int, n)
+	key := make([]int, n)
+	for v := range parent {
+		parent[v] = -1
+		key[v] = v
+	}
+
+	// link every element below a random earlier one, in random order
+	// of the children, evaluating random elements in between
+	order := rng.Perm(n - 1)
+	for _, c := range order {
+		child := c + 1
+		p := rng.Intn(child)
+		key[child] = rng.Intn(n)
+		f.SetKey(child, key[child])
+		f.Link(p, child)
+		parent[child] = p
+
+		for i := 0; i < 5; i++ {
+			v := rng.Intn(n)
+			got, want := f.Eval(v), naiveEval(parent, key, v)
+			if key[got] != key[want] {
+				t.Fatalf("Eval(%d) = %d (key %d), want %d (key %d)", v, got, key[got], want, key[want])
+			}
+		}
+	}
+	if f.Key(7) != key[7] {
+		t.Fatalf("Key(7) = %d, want %d", f.Key(7), key[7])
+	}
+}
+
+// TestLinkEvalDeepChain ensures long chains are compressed iteratively.
+func TestLinkEvalDeepChain(t *testing.T) {
+	const n = 200_000
+	f := NewLinkEval(n)
+	for v := 1; v < n; v++ {
+		f.SetKey(v, n-v)
+		f.Link(v-1, v)
+	}
+	// keys decrease with depth, so the deepest element is the minimum
+	if got := f.Eval(n - 1); got != n-1 {
+		t.Fatalf("Eval(%d) = %d", n-1, got)
+	}
+	if got := f.Eval(0); got != 0 {
+		t.Fatalf("Eval of a root should return the root, got %d", got)
+	}
+}
+
+// TestLinkEvalPanics ensures invalid arguments panic.
+func TestLinkEvalPanics(t *testing.T) {
+	calls := map[string]func(f *LinkEval){
+		"Key":      func(f *LinkEval) { f.Key(5) },
+		"SetKey":   func(f *LinkEval) { f.SetKey(-1, 0) },
+		"Link":     func(f *LinkEval) { f.Link(0, 9) },
+		"LinkRoot": func(f *LinkEval) { f.Link(0, 1); f.Link(2, 1) },
+		"Eval":     func(f *LinkEval) { f.Eval(3) },
+	}
+	for name, call := range calls {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("expected panic in %s, got none", name)
+				}
+			}()
+			call(NewLinkEval(3))
+		}()
+	}
+}
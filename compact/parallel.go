@@ -0,0 +1,182 @@
+package compact
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// NewParallel creates a DSU for elements in the range [0, size) in which
+// the elements of every edge are merged, using up to workers goroutines.
+//
+// The workers share one int32 parent slice and hook the edges with
+// compare-and-swap in the style of Shiloach–Vishkin, as the cc package
+// does: the larger of two roots is always hooked onto the smaller one, so
+// after every path is shortcut, every element points at the minimum element
+// of its set. The DSU is then filled in from that slice, page by page.
+// The result is therefore identical across runs and across worker counts,
+// and equal to what Canonicalize produces after the same unions done
+// sequentially. Besides the DSU, the loader needs only the parent slice.
+//
+// If workers <= 0, runtime.GOMAXPROCS(0) is used. Sizes beyond
+// math.MaxInt32 are loaded sequentially.
+// Panics if any edge has an element out of range.
+func NewParallel(size int, edges [][2]int, workers int) *DSU {
+	if size < 0 {
+		size = 0
+	}
+	for _, e := range edges {
+		if e[0] < 0 || e[0] >= size || e[1] < 0 || e[1] >= size {
+			panic("compact.DSU: index out of range in NewParallel")
+		}
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if size > math.MaxInt32 {
+		dsu := New(size)
+		for _, e := range edges {
+			dsu.union(e[0], e[1])
+		}
+		dsu.Canonicalize()
+		return dsu
+	}
+
+	parent := make([]int32, size)
+	chunks(size, workers, func(lo, hi int) {
+		for x := lo; x < hi; x++ {
+			parent[x] = int32(x)
+		}
+	})
+	chunks(len(edges), workers, func(lo, hi int) {
+		for _, e := range edges[lo:hi] {
+			hook(parent, int32(e[0]), int32(e[1]))
+		}
+	})
+	chunks(size, workers, func(lo, hi int) {
+		for x := lo; x < hi; x++ {
+			shortcut(parent, x)
+		}
+	})
+
+	// mark every root with other elements by flipping its entry to ^root
+	chunks(size, workers, func(lo, hi int) {
+		for x := lo; x < hi; x++ {
+			if p := atomic.LoadInt32(&parent[x]); p >= 0 && int(p) != x {
+				atomic.StoreInt32(&parent[p], ^p)
+			}
+		}
+	})
+
+	numPages := (size + pageSize - 1) >> pageShift
+	dsu := &DSU{
+		parent:       make([][]int, numPages),
+		rank:         make([][]int, numPages),
+		parentShared: make([]bool, numPages),
+		rankShared:   make([]bool, numPages),
+		size:         size,
+	}
+	chunks(numPages, workers, func(lo, hi int) {
+		for pg := lo; pg < hi; pg++ {
+			base := pg << pageShift
+			n := min(pageSize, size-base)
+			dsu.parent[pg] = make([]int, n)
+			dsu.rank[pg] = make([]int, n)
+			for i := 0; i < n; i++ {
+				if p := parent[base+i]; p < 0 {
+					dsu.parent[pg][i], dsu.rank[pg][i] = base+i, 1
+				} else {
+					dsu.parent[pg][i] = int(p)
+				}
+			}
+		}
+	})
+	return dsu
+}
+
+// hook merges the trees of the shared parent slice containing x and y by
+// hooking the larger of the two roots onto the smaller one.
+func hook(parent []int32, x, y int32) {
+	p1 := atomic.LoadInt32(&parent[x])
+	p2 := atomic.LoadInt32(&parent[y])
+	for p1 != p2 {
+		high, low := max(p1, p2), min(p1, p2)
+		pHigh := atomic.LoadInt32(&parent[high])
+		if pHigh == low {
+			return
+		}
+		if pHigh == high && atomic.CompareAndSwapInt32(&parent[high], high, low) {
+			return
+		}
+		p1 = atomic.LoadInt32(&parent[atomic.LoadInt32(&parent[high])])
+		p2 = atomic.LoadInt32(&parent[low])
+	}
+}
+
+// shortcut points x directly at its root in the shared parent slice.
+func shortcut(parent []int32, x int) {
+	for {
+		p := atomic.LoadInt32(&parent[x])
+		pp := atomic.LoadInt32(&parent[p])
+		if p == pp {
+			return
+		}
+		atomic.StoreInt32(&parent[x], pp)
+	}
+}
+
+// chunks calls fn on up to workers contiguous chunks covering [0, n),
+// concurrently.
+func chunks(n, workers int, fn func(lo, hi int)) {
+	per := (n + workers - 1) / max(workers, 1)
+	if per >= n {
+		fn(0, n)
+		return
+	}
+	var wg sync.WaitGroup
+	for lo := 0; lo < n; lo += per {
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			fn(lo, hi)
+		}(lo, min(lo+per, n))
+	}
+	wg.Wait()
+}
+
+// Canonicalize rewrites the forest so that the root of every set is its
+// minimum element and every other element points directly at it.
+//
+// The partition is unchanged; only the representatives returned by Find
+// and the keys of Groups become independent of the order of the unions.
+// Until the next successful Union, Find does not modify the DSU and may be
+// called from several goroutines concurrently.
+func (dsu *DSU) Canonicalize() {
+	// roots[x] is the current root of x
+	roots := make([]int, dsu.size)
+	for x := range roots {
+		roots[x] = dsu.find(x)
+	}
+
+	// minOf[r] is the minimum element of the set rooted at r; scanning in
+	// ascending order makes the first element seen the minimum.
+	minOf := make([]int, dsu.size)
+	for x := range minOf {
+		minOf[x] = -1
+	}
+	for x, r := range roots {
+		if minOf[r] < 0 {
+			minOf[r] = x
+		}
+	}
+
+	for x, r := range roots {
+		m := minOf[r]
+		dsu.setParent(x, m)
+		dsu.setRank(x, 0)
+		if m != x {
+			dsu.setRank(m, 1)
+		}
+	}
+}
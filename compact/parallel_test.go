@@ -0,0 +1,83 @@
+package compact
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// randomEdges returns m random edges over [0, size) from a fixed seed.
+func randomEdges(size, m int, seed int64) [][2]int {
+	rng := rand.New(rand.NewSource(seed))
+	edges := make([][2]int, m)
+	for i := range edges {
+		edges[i] = [2]int{rng.Intn(size), rng.Intn(size)}
+	}
+	return edges
+}
+
+// roots returns Find(x) for every element.
+func roots(dsu *DSU) []int {
+	out := make([]int, dsu.size)
+	for x := range out {
+		out[x] = dsu.Find(x)
+	}
+	return out
+}
+
+// TestNewParallelDeterministic ensures the result does not depend on the
+// number of workers and matches sequential unions followed by Canonicalize.
+func TestNewParallelDeterministic(t *testing.T) {
+	const size = 3*pageSize + 17
+	edges := randomEdges(size, size, 1)
+
+	seq := New(size)
+	seq.UnionPairs(edges)
+	seq.Canonicalize()
+	want := roots(seq)
+
+	for _, workers := range []int{0, 1, 2, 3, 8, 64} {
+		got := roots(NewParallel(size, edges, workers))
+		if !slices.Equal(got, want) {
+			t.Fatalf("workers=%d: roots differ from the sequential result", workers)
+		}
+	}
+}
+
+// TestCanonicalizeMinimumRoot ensures every root is its set's minimum
+// element and the DSU stays usable afterwards.
+func TestCanonicalizeMinimumRoot(t *testing.T) {
+	dsu := New(10)
+	dsu.Union(9, 4)
+	dsu.Union(4, 7)
+	dsu.Union(8, 2)
+	dsu.Canonicalize()
+
+	for x, want := range []int{0, 1, 2, 3, 4, 5, 6, 4, 2, 4} {
+		if got := dsu.Find(x); got != want {
+			t.Fatalf("Find(%d) = %d, want %d", x, got, want)
+		}
+	}
+
+	dsu.Union(7, 2)
+	if !dsu.Connected(9, 8) {
+		t.Fatal("expected 9 and 8 to be connected after a later union")
+	}
+}
+
+// TestNewParallelEdgeCases covers empty inputs and out-of-range edges.
+func TestNewParallelEdgeCases(t *testing.T) {
+	if got := len(NewParallel(5, nil, 4).Groups()); got != 5 {
+		t.Fatalf("expected 5 singleton groups, got %d", got)
+	}
+	if got := len(NewParallel(-1, nil, 4).Groups()); got != 0 {
+		t.Fatalf("expected no groups, got %d", got)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected panic on out-of-range edge, got none")
+		}
+	}()
+	NewParallel(5, [][2]int{{0, 5}}, 2)
+}
@@ -0,0 +1,127 @@
+package compact
+
+import (
+	"github.com/arunksaha/gdsu"
+	"github.com/arunksaha/gdsu/internal/forest"
+)
+
+// Edge is a union recorded by a Witness: the arguments of a successful
+// Union and the label it was given.
+type Edge[L any] struct {
+	X, Y  int
+	Label L
+}
+
+// Witness is a DSU over the fixed range [0, n) that retains every
+// successful union as an edge of a spanning forest, so that it can explain
+// why two elements are connected.
+//
+// The forest is stored with parent pointers. Union reroots the smaller of
+// the two trees at its endpoint of the new edge and hangs it below the
+// other endpoint, which takes O(log n) amortized time per union; Path
+// climbs from both elements in turn towards their lowest common ancestor,
+// in time linear in the length of the path.
+type Witness[L any] struct {
+	dsu *DSU
+
+	// size[r] is the number of elements of the set whose root in dsu is r;
+	// unused for other elements.
+	size []int
+
+	// forest holds the recorded edges, each labeled with itself.
+	forest *forest.Dense[Edge[L]]
+}
+
+// NewWitness creates a Witness for elements in the range [0, size).
+func NewWitness[L any](size int) *Witness[L] {
+	if size < 0 {
+		size = 0
+	}
+	w := &Witness[L]{
+		dsu:    New(size),
+		size:   make([]int, size),
+		forest: forest.NewDense[Edge[L]](size),
+	}
+	for x := 0; x < size; x++ {
+		w.size[x] = 1
+	}
+	return w
+}
+
+// Find returns the representative element (root) of the set containing x.
+// Panics if x is out of range.
+func (w *Witness[L]) Find(x int) int {
+	return w.dsu.Find(x)
+}
+
+// Union merges the sets containing x and y, recording the union with the
+// zero label. Returns true if the sets were separate and are now merged.
+// Panics if x or y are out of range.
+func (w *Witness[L]) Union(x, y int) bool {
+	var zero L
+	_, merged := w.UnionWith(x, y, zero)
+	return merged
+}
+
+// UnionWith merges the sets containing x and y and records the union with
+// the given label. Returns true if the sets were separate and are now
+// merged. Otherwise nothing is recorded, and the returned path is the one
+// Path(x, y) reports, which together with the rejected union forms a
+// cycle.
+// Panics if x or y are out of range.
+func (w *Witness[L]) UnionWith(x, y int, label L) ([]Edge[L], bool) {
+	if !w.dsu.boundsCheck(x) || !w.dsu.boundsCheck(y) {
+		panic("compact.Witness: index out of range in UnionWith")
+	}
+	rootX, rootY := w.dsu.find(x), w.dsu.find(y)
+	if rootX == rootY {
+		return w.forest.Path(x, y), false
+	}
+
+	// hang the smaller tree below the larger one
+	child, parent := x, y
+	if w.size[rootX] > w.size[rootY] {
+		child, parent = y, x
+	}
+	w.forest.Link(child, parent, Edge[L]{X: x, Y: y, Label: label})
+
+	total := w.size[rootX] + w.size[rootY]
+	w.dsu.union(rootX, rootY)
+	w.size[w.dsu.find(rootX)] = total
+	return nil, true
+}
+
+// Path returns the recorded unions on the forest path from x to y, in
+// order, and true; consecutive edges share an element, but each edge keeps
+// the orientation of its Union call. If x and y are not connected, Path
+// returns false.
+// Panics if x or y are out of range.
+func (w *Witness[L]) Path(x, y int) ([]Edge[L], bool) {
+	if !w.dsu.boundsCheck(x) || !w.dsu.boundsCheck(y) {
+		panic("compact.Witness: index out of range in Path")
+	}
+	if w.dsu.find(x) != w.dsu.find(y) {
+		return nil, false
+	}
+	return w.forest.Path(x, y), true
+}
+
+// Connected reports whether x and y are in the same set.
+// Panics if x or y are out of range.
+func (w *Witness[L]) Connected(x, y int) bool {
+	return w.dsu.Connected(x, y)
+}
+
+// Groups returns a map from root -> slice of elements in that set.
+func (w *Witness[L]) Groups() map[int][]int {
+	return w.dsu.Groups()
+}
+
+// Edges returns every recorded union, ordered by the element below it in
+// the forest.
+func (w *Witness[L]) Edges() []Edge[L] {
+	return w.forest.Labels()
+}
+
+// Compile-time assertion that Witness implements gdsu.DSU[int].
+var _ gdsu.DSU[int] = (*Witness[string])(nil)
@@ -0,0 +1,100 @@
+package compact
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkWalk ensures path is a walk from x to y along recorded edges that
+// uses no edge twice.
+func checkWalk(t *testing.T, recorded map[Edge[int]]bool, path []Edge[int], x, y int) {
+	t.Helper()
+	used := make(map[Edge[int]]bool)
+	at := x
+	for _, e := range path {
+		if !recorded[e] || used[e] {
+			t.Fatalf("path %v from %d to %d uses edge %v wrongly", path, x, y, e)
+		}
+		used[e] = true
+		switch at {
+		case e.X:
+			at = e.Y
+		case e.Y:
+			at = e.X
+		default:
+			t.Fatalf("path %v from %d to %d is not a walk at %v", path, x, y, e)
+		}
+	}
+	if at != y {
+		t.Fatalf("path %v from %d ends at %d, want %d", path, x, at, y)
+	}
+}
+
+// TestWitnessPaths performs random labeled unions and checks every path,
+// including the cycles reported for rejected unions.
+func TestWitnessPaths(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 200
+	w := NewWitness[int](n)
+	ref := New(n)
+	recorded := make(map[Edge[int]]bool)
+	for i := 0; i < 3*n; i++ {
+		x, y := rng.Intn(n), rng.Intn(n)
+		cycle, merged := w.UnionWith(x, y, i)
+		if merged != ref.Union(x, y) {
+			t.Fatalf("UnionWith(%d, %d) = %v, want %v", x, y, merged, !merged)
+		}
+		if merged {
+			if cycle != nil {
+				t.Fatalf("UnionWith(%d, %d) returned a cycle %v on merge", x, y, cycle)
+			}
+			recorded[Edge[int]{X: x, Y: y, Label: i}] = true
+		} else {
+			checkWalk(t, recorded, cycle, x, y)
+		}
+
+		a, b := rng.Intn(n), rng.Intn(n)
+		path, ok := w.Path(a, b)
+		if ok != ref.Connected(a, b) {
+			t.Fatalf("Path(%d, %d) reported %v, want %v", a, b, ok, !ok)
+		}
+		if ok {
+			checkWalk(t, recorded, path, a, b)
+		}
+	}
+	if len(w.Edges()) != len(recorded) {
+		t.Fatalf("expected %d edges, got %d", len(recorded), len(w.Edges()))
+	}
+}
+
+// TestWitnessInterface checks the gdsu.DSU methods and the panics.
+func TestWitnessInterface(t *testing.T) {
+	w := NewWitness[string](4)
+	if !w.Union(0, 1) || w.Union(1, 0) || !w.Connected(0, 1) || w.Connected(0, 2) {
+		t.Fatalf("unexpected connectivity")
+	}
+	if len(w.Groups()) != 3 || w.Find(1) != w.Find(0) {
+		t.Fatalf("unexpected groups %v", w.Groups())
+	}
+	if path, ok := w.Path(2, 2); !ok || len(path) != 0 {
+		t.Fatalf("Path(2, 2) = %v, %v", path, ok)
+	}
+	if _, ok := w.Path(0, 3); ok {
+		t.Fatalf("expected no path between 0 and 3")
+	}
+	_ = NewWitness[string](-1)
+
+	for _, fn := range []func(){
+		func() { w.UnionWith(0, 4, "") },
+		func() { w.Path(-1, 0) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatal("expected panic on out-of-range element, got none")
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/arunksaha/gdsu"
+	"github.com/arunksaha/gdsu/cc"
 	"github.com/arunksaha/gdsu/compact"
 	"github.com/arunksaha/gdsu/sparse"
+	"github.com/arunksaha/gdsu/static"
 )
 
 const NumElements = 100_000
@@ -59,3 +62,154 @@ func BenchmarkCompareFind(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkCompareUnionPairs compares per-pair Union() calls through the
+// gdsu.DSU interface with a single UnionPairs() batch call.
+func BenchmarkCompareUnionPairs(b *testing.B) {
+	pairs := make([][2]int, NumElements)
+	for i := range pairs {
+		pairs[i] = [2]int{rand.Intn(NumElements), rand.Intn(NumElements)}
+	}
+
+	run := func(b *testing.B, newDSU func() gdsu.BatchDSU[int]) {
+		b.Run("Interface", func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				b.StopTimer()
+				var dsu gdsu.DSU[int] = newDSU()
+				b.StartTimer()
+				for _, p := range pairs {
+					dsu.Union(p[0], p[1])
+				}
+			}
+		})
+		b.Run("Batch", func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				b.StopTimer()
+				dsu := newDSU()
+				b.StartTimer()
+				dsu.UnionPairs(pairs)
+			}
+		})
+	}
+
+	b.Run("Sparse", func(b *testing.B) {
+		run(b, func() gdsu.BatchDSU[int] { return sparse.New[int]() })
+	})
+	b.Run("Compact", func(b *testing.B) {
+		run(b, func() gdsu.BatchDSU[int] { return compact.New(NumElements) })
+	})
+}
+
+// BenchmarkCompareParallelLoad compares building a compact DSU from a large
+// edge list with the sequential Union loop and with compact.NewParallel.
+func BenchmarkCompareParallelLoad(b *testing.B) {
+	edges := make([][2]int, 4*NumElements)
+	for i := range edges {
+		edges[i] = [2]int{rand.Intn(NumElements), rand.Intn(NumElements)}
+	}
+
+	b.Run("Sequential", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			b.StopTimer()
+			dsu := compact.New(NumElements)
+			b.StartTimer()
+			for _, e := range edges {
+				dsu.Union(e[0], e[1])
+			}
+		}
+	})
+
+	b.Run("SequentialCanonical", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			b.StopTimer()
+			dsu := compact.New(NumElements)
+			b.StartTimer()
+			for _, e := range edges {
+				dsu.Union(e[0], e[1])
+			}
+			dsu.Canonicalize()
+		}
+	})
+
+	b.Run("Parallel", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_ = compact.NewParallel(NumElements, edges, 0)
+		}
+	})
+}
+
+// BenchmarkCompareComponents compares labeling the connected components of
+// a random graph with a sequential compact DSU, with cc.Components on the
+// edge list, and with cc.Afforest on a prebuilt graph.
+func BenchmarkCompareComponents(b *testing.B) {
+	edges := make([][2]int, 4*NumElements)
+	for i := range edges {
+		edges[i] = [2]int{rand.Intn(NumElements), rand.Intn(NumElements)}
+	}
+
+	b.Run("Compact", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			dsu := compact.New(NumElements)
+			dsu.UnionPairs(edges)
+			labels := make([]int, NumElements)
+			for v := range labels {
+				labels[v] = dsu.Find(v)
+			}
+		}
+	})
+
+	b.Run("Components", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_ = cc.Components(NumElements, edges, 0)
+		}
+	})
+
+	b.Run("Afforest", func(b *testing.B) {
+		g := cc.NewGraph(NumElements, edges, 0)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			_ = cc.Afforest(g, 0)
+		}
+	})
+}
+
+// BenchmarkCompareStatic compares compact and static on unions along the
+// edges of a random union tree, declared up front for static, interleaved
+// with Find queries.
+func BenchmarkCompareStatic(b *testing.B) {
+	parent := make([]int, NumElements)
+	for x := 1; x < NumElements; x++ {
+		parent[x] = rand.Intn(x)
+	}
+	order := rand.Perm(NumElements)
+	queries := make([]int, NumElements)
+	for i := range queries {
+		queries[i] = rand.Intn(NumElements)
+	}
+
+	run := func(b *testing.B, newDSU func() gdsu.DSU[int]) {
+		for n := 0; n < b.N; n++ {
+			b.StopTimer()
+			dsu := newDSU()
+			b.StartTimer()
+			for i, x := range order {
+				if x != 0 {
+					dsu.Union(x, parent[x])
+				}
+				_ = dsu.Find(queries[i])
+			}
+		}
+	}
+
+	b.Run("Compact", func(b *testing.B) {
+		run(b, func() gdsu.DSU[int] { return compact.New(NumElements) })
+	})
+	b.Run("Static", func(b *testing.B) {
+		run(b, func() gdsu.DSU[int] { return static.New(parent) })
+	})
+	b.Run("StaticConstruct", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_ = static.New(parent)
+		}
+	})
+}
@@ -0,0 +1,240 @@
+// Package congruence decides equalities between terms built from
+// uninterpreted functions with congruence closure: if a = b, then
+// f(a) = f(b).
+//
+// Terms are hash-consed, so structurally equal terms are the same Term,
+// and the equivalence classes are the sets of a sparse.DSU. Every class
+// keeps a use-list of the applications that have an argument in it, and a
+// signature table maps every application, with its arguments replaced by
+// the names of their classes, to a term. When two classes merge, the class
+// with the shorter use-list is absorbed, and the applications on its
+// use-list get new signatures; any that now collide with another
+// application are congruent, and their classes are merged in turn
+// (Downey, Sethi and Tarjan, 1980).
+package congruence
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// Term is a hash-consed term of a Closure.
+type Term int
+
+// node is the structure of a term: a function symbol applied to arguments,
+// or a constant if there are none.
+type node struct {
+	fn   string
+	args []Term
+}
+
+// Closure is a set of terms closed under asserted equalities and
+// congruence.
+type Closure struct {
+	dsu *sparse.DSU[Term]
+
+	// terms[t] is the structure of t, and index maps the key of every
+	// structure to its term.
+	terms []node
+	index map[string]Term
+
+	// name maps the root of every set of dsu to the name of its class, a
+	// term of the class that stays the same when the class absorbs a class
+	// with a shorter use-list, whichever root the DSU keeps.
+	name map[Term]Term
+
+	// uses maps the name of every class to the applications that have an
+	// argument in it.
+	uses map[Term][]Term
+
+	// sig maps the signature of every application, i.e., the key of its
+	// function symbol with the names of the classes of its arguments, to
+	// an application with that signature.
+	sig map[string]Term
+}
+
+// New creates an empty Closure.
+func New() *Closure {
+	return &Closure{
+		dsu:   sparse.New[Term](),
+		index: make(map[string]Term),
+		name:  make(map[Term]Term),
+		uses:  make(map[Term][]Term),
+		sig:   make(map[string]Term),
+	}
+}
+
+// key encodes a function symbol and arguments as a map key.
+func key(fn string, args []Term) string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(len(fn)))
+	b.WriteByte(':')
+	b.WriteString(fn)
+	for _, a := range args {
+		b.WriteByte(',')
+		b.WriteString(strconv.Itoa(int(a)))
+	}
+	return b.String()
+}
+
+// class returns the name of the class of t.
+func (c *Closure) class(t Term) Term {
+	return c.name[c.dsu.Find(t)]
+}
+
+// signature returns the signature of the application t.
+func (c *Closure) signature(t Term) string {
+	n := c.terms[t]
+	names := make([]Term, len(n.args))
+	for i, a := range n.args {
+		names[i] = c.class(a)
+	}
+	return key(n.fn, names)
+}
+
+// check panics if t is not a term of c; method names the caller.
+func (c *Closure) check(t Term, method string) {
+	if t < 0 || int(t) >= len(c.terms) {
+		panic("congruence.Closure: unknown term in " + method)
+	}
+}
+
+// Const returns the constant named name.
+func (c *Closure) Const(name string) Term {
+	return c.App(name)
+}
+
+// App returns the application of the function fn to args, which must be
+// terms of c. If the application is congruent to an existing one, the two
+// are equal from the start.
+// Panics if any argument is not a term of c.
+func (c *Closure) App(fn string, args ...Term) Term {
+	for _, a := range args {
+		c.check(a, "App")
+	}
+	k := key(fn, args)
+	if t, ok := c.index[k]; ok {
+		return t
+	}
+
+	t := Term(len(c.terms))
+	c.terms = append(c.terms, node{fn: fn, args: slices.Clone(args)})
+	c.index[k] = t
+	c.name[c.dsu.Find(t)] = t
+	if len(args) == 0 {
+		return t
+	}
+
+	for _, a := range args {
+		n := c.class(a)
+		c.uses[n] = append(c.uses[n], t)
+	}
+	s := c.signature(t)
+	if u, ok := c.sig[s]; ok {
+		c.merge(t, u)
+	} else {
+		c.sig[s] = t
+	}
+	return t
+}
+
+// Assert asserts that a and b are equal and propagates the consequences.
+// Panics if a or b is not a term of c.
+func (c *Closure) Assert(a, b Term) {
+	c.check(a, "Assert")
+	c.check(b, "Assert")
+	c.merge(a, b)
+}
+
+// merge merges the classes of a and b and of every pair of applications
+// that becomes congruent as a result.
+func (c *Closure) merge(a, b Term) {
+	pending := [][2]Term{{a, b}}
+	for len(pending) > 0 {
+		p := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		keep, absorbed := c.class(p[0]), c.class(p[1])
+		if keep == absorbed {
+			continue
+		}
+		if len(c.uses[keep]) < len(c.uses[absorbed]) {
+			keep, absorbed = absorbed, keep
+		}
+
+		// the signatures of the applications using the absorbed class
+		// change: remove them before the union and reinsert them after,
+		// collecting the applications they now collide with
+		moved := c.uses[absorbed]
+		for _, u := range moved {
+			s := c.signature(u)
+			if v, ok := c.sig[s]; ok && v == u {
+				delete(c.sig, s)
+			}
+		}
+		delete(c.name, c.dsu.Find(p[0]))
+		delete(c.name, c.dsu.Find(p[1]))
+		c.dsu.Union(p[0], p[1])
+		c.name[c.dsu.Find(p[0])] = keep
+		for _, u := range moved {
+			s := c.signature(u)
+			if v, ok := c.sig[s]; !ok {
+				c.sig[s] = u
+			} else if v != u {
+				pending = append(pending, [2]Term{u, v})
+			}
+		}
+		c.uses[keep] = append(c.uses[keep], moved...)
+		delete(c.uses, absorbed)
+	}
+}
+
+// AreEqual reports whether a and b are equal as a consequence of the
+// asserted equalities.
+// Panics if a or b is not a term of c.
+func (c *Closure) AreEqual(a, b Term) bool {
+	c.check(a, "AreEqual")
+	c.check(b, "AreEqual")
+	return c.dsu.Connected(a, b)
+}
+
+// Classes returns the equivalence classes of all terms. Every class is
+// sorted, and the classes are ordered by their smallest term.
+func (c *Closure) Classes() [][]Term {
+	var classes [][]Term
+	for _, members := range c.dsu.Groups() {
+		slices.Sort(members)
+		classes = append(classes, members)
+	}
+	slices.SortFunc(classes, func(a, b []Term) int { return int(a[0] - b[0]) })
+	return classes
+}
+
+// Format returns the term t written as f(x, y), or as its name if it is a
+// constant.
+// Panics if t is not a term of c.
+func (c *Closure) Format(t Term) string {
+	c.check(t, "Format")
+	var b strings.Builder
+	c.format(&b, t)
+	return b.String()
+}
+
+// format writes t to b.
+func (c *Closure) format(b *strings.Builder, t Term) {
+	n := c.terms[t]
+	b.WriteString(n.fn)
+	if len(n.args) == 0 {
+		return
+	}
+	b.WriteByte('(')
+	for i, a := range n.args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		c.format(b, a)
+	}
+	b.WriteByte(')')
+}
@@ -0,0 +1,33 @@
+package congruence
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Example derives f(a) = f(b) from a = b.
+func Example() {
+	c := New()
+	a, b := c.Const("a"), c.Const("b")
+	fa, fb := c.App("f", a), c.App("f", b)
+	gfa := c.App("g", fa)
+
+	fmt.Println(c.AreEqual(fa, fb))
+	c.Assert(a, b)
+	fmt.Println(c.AreEqual(fa, fb), c.AreEqual(gfa, c.App("g", fb)))
+
+	for _, class := range c.Classes() {
+		names := make([]string, len(class))
+		for i, t := range class {
+			names[i] = c.Format(t)
+		}
+		fmt.Println(strings.Join(names, " "))
+	}
+
+	// Output:
+	// false
+	// true true
+	// a b
+	// f(a) f(b)
+	// g(f(a)) g(f(b))
+}
@@ -0,0 +1,152 @@
+package congruence
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/arunksaha/gdsu/compact"
+)
+
+// naiveClosure computes the congruence closure of the asserted equalities
+// over the terms of c by repeatedly merging congruent applications until
+// nothing changes.
+func naiveClosure(c *Closure, asserted [][2]Term) *compact.DSU {
+	dsu := compact.New(len(c.terms))
+	for _, a := range asserted {
+		dsu.Union(int(a[0]), int(a[1]))
+	}
+	for changed := true; changed; {
+		changed = false
+		for s := range c.terms {
+			for t := s + 1; t < len(c.terms); t++ {
+				ns, nt := c.terms[s], c.terms[t]
+				if ns.fn != nt.fn || len(ns.args) != len(nt.args) || len(ns.args) == 0 || dsu.Connected(s, t) {
+					continue
+				}
+				congruent := true
+				for i := range ns.args {
+					if !dsu.Connected(int(ns.args[i]), int(nt.args[i])) {
+						congruent = false
+						break
+					}
+				}
+				if congruent {
+					dsu.Union(s, t)
+					changed = true
+				}
+			}
+		}
+	}
+	return dsu
+}
+
+// TestClosureMatchesNaive builds random term DAGs with random equalities
+// and compares every pair of terms with the naive closure.
+func TestClosureMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 40; trial++ {
+		c := New()
+		var terms []Term
+		for _, name := range []string{"a", "b", "c", "d"} {
+			terms = append(terms, c.Const(name))
+		}
+		var asserted [][2]Term
+		for step := 0; step < 40; step++ {
+			if rng.Intn(3) == 0 {
+				x, y := terms[rng.Intn(len(terms))], terms[rng.Intn(len(terms))]
+				c.Assert(x, y)
+				asserted = append(asserted, [2]Term{x, y})
+			} else {
+				fn := []string{"f", "g"}[rng.Intn(2)]
+				args := make([]Term, 1+rng.Intn(2))
+				for i := range args {
+					args[i] = terms[rng.Intn(len(terms))]
+				}
+				terms = append(terms, c.App(fn, args...))
+			}
+		}
+
+		want := naiveClosure(c, asserted)
+		for x := range c.terms {
+			for y := range c.terms {
+				if got := c.AreEqual(Term(x), Term(y)); got != want.Connected(x, y) {
+					t.Fatalf("trial %d: AreEqual(%s, %s) = %v, want %v", trial, c.Format(Term(x)), c.Format(Term(y)), got, !got)
+				}
+			}
+		}
+		if len(c.Classes()) != len(want.Groups()) {
+			t.Fatalf("trial %d: %d classes, want %d", trial, len(c.Classes()), len(want.Groups()))
+		}
+	}
+}
+
+// TestClosureHashConsing checks that equal structures are the same term.
+func TestClosureHashConsing(t *testing.T) {
+	c := New()
+	a, b := c.Const("a"), c.Const("b")
+	if c.App("f", a, b) != c.App("f", a, b) || c.App("f", a, b) == c.App("f", b, a) {
+		t.Fatalf("unexpected hash-consing")
+	}
+	// symbols are not confused with argument lists
+	if c.Const("f,0") == c.App("f", a) {
+		t.Fatalf("distinct terms share a key")
+	}
+	if got := c.Format(c.App("g", c.App("f", a, b), a)); got != "g(f(a, b), a)" {
+		t.Fatalf("Format = %s", got)
+	}
+}
+
+// TestClosureTransitiveCongruence derives f(a) = a from f(f(f(a))) = a and
+// f(f(f(f(f(a))))) = a, the classic example of Nelson and Oppen.
+func TestClosureTransitiveCongruence(t *testing.T) {
+	c := New()
+	a := c.Const("a")
+	f := []Term{a}
+	for i := 1; i <= 5; i++ {
+		f = append(f, c.App("f", f[i-1]))
+	}
+	c.Assert(f[3], a)
+	c.Assert(f[5], a)
+	if !c.AreEqual(f[1], a) {
+		t.Fatalf("expected f(a) = a")
+	}
+	if len(c.Classes()) != 1 {
+		t.Fatalf("expected a single class, got %v", c.Classes())
+	}
+}
+
+// TestClosureAppAfterAssert checks that new applications join the classes
+// of congruent existing ones.
+func TestClosureAppAfterAssert(t *testing.T) {
+	c := New()
+	a, b := c.Const("a"), c.Const("b")
+	fa := c.App("f", a)
+	c.Assert(a, b)
+	if fb := c.App("f", b); !c.AreEqual(fa, fb) {
+		t.Fatalf("expected f(a) = f(b)")
+	}
+	if c.AreEqual(fa, a) {
+		t.Fatalf("unexpected f(a) = a")
+	}
+}
+
+// TestClosurePanics ensures unknown terms panic.
+func TestClosurePanics(t *testing.T) {
+	c := New()
+	a := c.Const("a")
+	for _, fn := range []func(){
+		func() { c.App("f", a+1) },
+		func() { c.Assert(a, -1) },
+		func() { c.AreEqual(a, 7) },
+		func() { c.Format(3) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatal("expected panic on unknown term, got none")
+				}
+			}()
+			fn()
+		}()
+	}
+}
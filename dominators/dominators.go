@@ -0,0 +1,93 @@
+// Package dominators computes dominator trees of control-flow graphs.
+//
+// A node d dominates a node v if every path from the entry to v passes
+// through d; the immediate dominator of v is its closest strict dominator.
+// Immediate dominators are computed with the Lengauer–Tarjan algorithm on
+// top of a compact.LinkEval forest. Nodes may be of any comparable type;
+// they are numbered internally in depth-first order, and the depth-first
+// search is iterative, so deep graphs do not overflow the goroutine stack.
+package dominators
+
+import "github.com/arunksaha/gdsu/compact"
+
+// Immediate returns the immediate dominator of every node reachable from
+// entry in the control-flow graph given by the successor lists succ.
+// The entry node and unreachable nodes have no entry in the result.
+func Immediate[T comparable](entry T, succ map[T][]T) map[T]T {
+	// number the reachable nodes in depth-first preorder; parent[w] is
+	// the depth-first tree parent of w
+	nodes := []T{entry}
+	number := map[T]int{entry: 0}
+	parent := []int{-1}
+	type frame struct {
+		node int
+		next int
+	}
+	stack := []frame{{0, 0}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		out := succ[nodes[top.node]]
+		if top.next == len(out) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		y := out[top.next]
+		top.next++
+		if _, seen := number[y]; !seen {
+			w := len(nodes)
+			number[y] = w
+			nodes = append(nodes, y)
+			parent = append(parent, top.node)
+			stack = append(stack, frame{w, 0})
+		}
+	}
+
+	n := len(nodes)
+	pred := make([][]int, n)
+	for v, x := range nodes {
+		for _, y := range succ[x] {
+			w := number[y]
+			pred[w] = append(pred[w], v)
+		}
+	}
+
+	// the key of every node in the forest is its semidominator
+	forest := compact.NewLinkEval(n)
+	semi := make([]int, n)
+	idom := make([]int, n)
+	bucket := make([][]int, n)
+	for w := range semi {
+		semi[w] = w
+	}
+	for w := n - 1; w > 0; w-- {
+		for _, v := range pred[w] {
+			if u := forest.Eval(v); semi[u] < semi[w] {
+				semi[w] = semi[u]
+			}
+		}
+		bucket[semi[w]] = append(bucket[semi[w]], w)
+		p := parent[w]
+		forest.SetKey(w, semi[w])
+		forest.Link(p, w)
+
+		for _, v := range bucket[p] {
+			if u := forest.Eval(v); semi[u] < semi[v] {
+				idom[v] = u
+			} else {
+				idom[v] = p
+			}
+		}
+		bucket[p] = nil
+	}
+	for w := 1; w < n; w++ {
+		if idom[w] != semi[w] {
+			idom[w] = idom[idom[w]]
+		}
+	}
+
+	result := make(map[T]T, n-1)
+	for w := 1; w < n; w++ {
+		result[nodes[w]] = nodes[idom[w]]
+	}
+	return result
+}
@@ -0,0 +1,26 @@
+package dominators
+
+import "fmt"
+
+// Example computes the dominator tree of a loop containing a branch.
+func Example() {
+	succ := map[string][]string{
+		"entry": {"loop"},
+		"loop":  {"then", "else", "exit"},
+		"then":  {"latch"},
+		"else":  {"latch"},
+		"latch": {"loop"},
+	}
+
+	idom := Immediate("entry", succ)
+	for _, x := range []string{"loop", "then", "else", "latch", "exit"} {
+		fmt.Println(x, "<-", idom[x])
+	}
+
+	// Output:
+	// loop <- entry
+	// then <- loop
+	// else <- loop
+	// latch <- loop
+	// exit <- loop
+}
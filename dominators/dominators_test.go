@@ -0,0 +1,141 @@
+package dominators
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// naiveDominators computes the dominator sets of every node reachable from
+// entry by iterating the dataflow equations to a fixed point.
+func naiveDominators(entry, n int, succ map[int][]int) map[int]map[int]bool {
+	reachable := map[int]bool{entry: true}
+	queue := []int{entry}
+	for len(queue) > 0 {
+		x := queue[0]
+		queue = queue[1:]
+		for _, y := range succ[x] {
+			if !reachable[y] {
+				reachable[y] = true
+				queue = append(queue, y)
+			}
+		}
+	}
+	pred := map[int][]int{}
+	for x := range reachable {
+		for _, y := range succ[x] {
+			pred[y] = append(pred[y], x)
+		}
+	}
+
+	dom := map[int]map[int]bool{entry: {entry: true}}
+	for x := range reachable {
+		if x != entry {
+			dom[x] = map[int]bool{}
+			for y := range reachable {
+				dom[x][y] = true
+			}
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for x := 0; x < n; x++ {
+			if !reachable[x] || x == entry {
+				continue
+			}
+			next := map[int]bool{x: true}
+			for y := range dom[pred[x][0]] {
+				all := true
+				for _, p := range pred[x][1:] {
+					if !dom[p][y] {
+						all = false
+						break
+					}
+				}
+				if all {
+					next[y] = true
+				}
+			}
+			if len(next) != len(dom[x]) {
+				dom[x] = next
+				changed = true
+			}
+		}
+	}
+	return dom
+}
+
+// TestImmediateMatchesNaive cross-checks random graphs against dataflow
+// dominator sets: the immediate dominator of x is the strict dominator of
+// x that every other strict dominator dominates.
+func TestImmediateMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		n := 1 + rng.Intn(30)
+		succ := map[int][]int{}
+		for e := rng.Intn(3 * n); e > 0; e-- {
+			x, y := rng.Intn(n), rng.Intn(n)
+			succ[x] = append(succ[x], y)
+		}
+
+		dom := naiveDominators(0, n, succ)
+		idom := Immediate(0, succ)
+		if len(idom) != len(dom)-1 {
+			t.Fatalf("trial %d: got %d immediate dominators, want %d", trial, len(idom), len(dom)-1)
+		}
+		for x, d := range dom {
+			if x == 0 {
+				continue
+			}
+			var want int
+			for y := range d {
+				if y != x && len(dom[y]) == len(d)-1 {
+					want = y
+				}
+			}
+			if got, ok := idom[x]; !ok || got != want {
+				t.Fatalf("trial %d: idom(%d) = %d, %v, want %d", trial, x, got, ok, want)
+			}
+		}
+	}
+}
+
+// TestImmediateDiamond checks a small if-then-else with a loop.
+func TestImmediateDiamond(t *testing.T) {
+	succ := map[string][]string{
+		"entry": {"cond"},
+		"cond":  {"then", "else"},
+		"then":  {"join"},
+		"else":  {"join"},
+		"join":  {"cond", "exit"},
+		"dead":  {"exit"},
+	}
+	want := map[string]string{
+		"cond": "entry",
+		"then": "cond",
+		"else": "cond",
+		"join": "cond",
+		"exit": "join",
+	}
+	idom := Immediate("entry", succ)
+	if len(idom) != len(want) {
+		t.Fatalf("got %v, want %v", idom, want)
+	}
+	for x, d := range want {
+		if idom[x] != d {
+			t.Fatalf("idom(%s) = %s, want %s", x, idom[x], d)
+		}
+	}
+}
+
+// TestImmediateDeepChain ensures very deep graphs do not overflow the stack.
+func TestImmediateDeepChain(t *testing.T) {
+	const n = 300_000
+	succ := make(map[int][]int, n)
+	for x := 0; x+1 < n; x++ {
+		succ[x] = []int{x + 1, 0}
+	}
+	idom := Immediate(0, succ)
+	if len(idom) != n-1 || idom[n-1] != n-2 || idom[1] != 0 {
+		t.Fatalf("unexpected result of length %d", len(idom))
+	}
+}
@@ -0,0 +1,233 @@
+// Package egraph implements e-graphs with equality saturation, in the style
+// of egg (Willsey et al., 2021).
+//
+// An e-graph represents many equivalent expressions compactly. It consists
+// of e-classes, sets of equivalent e-nodes, and e-nodes, operators applied
+// to e-classes. The e-classes are the sets of a sparse.DSU, and e-nodes are
+// hash-consed: an e-node with canonical children, i.e., the
+// representatives of their e-classes, occurs at most once.
+//
+// Union only merges e-classes and records them for repair; the
+// congruence invariant, that equal e-nodes are in the same e-class, is
+// restored by Rebuild in a single pass over all pending repairs. Search
+// and Extract rebuild first if needed. Rewrite rules are pairs of patterns
+// matched against the e-graph by e-matching, and Run applies them until
+// saturation or a limit; Extract then picks the cheapest expression of an
+// e-class under a cost function.
+package egraph
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// ClassID identifies an e-class. After unions, several ids may identify the
+// same e-class; Find returns the canonical one.
+type ClassID int
+
+// Node is an e-node: an operator applied to e-classes. Constants and
+// variables are operators without children.
+type Node struct {
+	Op       string
+	Children []ClassID
+}
+
+// key encodes the node as a map key.
+func (n Node) key() string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(len(n.Op)))
+	b.WriteByte(':')
+	b.WriteString(n.Op)
+	for _, c := range n.Children {
+		b.WriteByte(',')
+		b.WriteString(strconv.Itoa(int(c)))
+	}
+	return b.String()
+}
+
+// parent is an e-node together with its e-class, recorded in the e-classes
+// of its children.
+type parent struct {
+	node  Node
+	class ClassID
+}
+
+// eclass is the data of an e-class, stored under its canonical id.
+type eclass struct {
+	nodes   []Node
+	parents []parent
+}
+
+// EGraph is an e-graph.
+type EGraph struct {
+	dsu *sparse.DSU[ClassID]
+
+	// classes maps every canonical id to its e-class.
+	classes map[ClassID]*eclass
+
+	// memo maps the key of every e-node, as of its last repair, to its
+	// e-class.
+	memo map[string]ClassID
+
+	// pending holds the e-classes whose parents need repair.
+	pending []ClassID
+
+	next ClassID
+}
+
+// New creates an empty e-graph.
+func New() *EGraph {
+	return &EGraph{
+		dsu:     sparse.New[ClassID](),
+		classes: make(map[ClassID]*eclass),
+		memo:    make(map[string]ClassID),
+	}
+}
+
+// Find returns the canonical id of the e-class of id.
+func (g *EGraph) Find(id ClassID) ClassID {
+	return g.dsu.Find(id)
+}
+
+// Equivalent reports whether a and b are in the same e-class.
+func (g *EGraph) Equivalent(a, b ClassID) bool {
+	return g.dsu.Connected(a, b)
+}
+
+// ClassCount returns the number of e-classes.
+func (g *EGraph) ClassCount() int {
+	return len(g.classes)
+}
+
+// NodeCount returns the number of distinct e-nodes.
+func (g *EGraph) NodeCount() int {
+	return len(g.memo)
+}
+
+// canonical returns n with canonical children.
+func (g *EGraph) canonical(n Node) Node {
+	children := make([]ClassID, len(n.Children))
+	for i, c := range n.Children {
+		children[i] = g.dsu.Find(c)
+	}
+	return Node{Op: n.Op, Children: children}
+}
+
+// Add returns the e-class of the e-node op(children...), adding it if it is
+// not present.
+// Panics if any child is not an e-class of g.
+func (g *EGraph) Add(op string, children ...ClassID) ClassID {
+	for _, c := range children {
+		if c < 0 || c >= g.next {
+			panic("egraph.EGraph: unknown e-class in Add")
+		}
+	}
+	n := g.canonical(Node{Op: op, Children: children})
+	k := n.key()
+	if id, ok := g.memo[k]; ok {
+		return g.dsu.Find(id)
+	}
+
+	id := g.next
+	g.next++
+	g.dsu.Find(id)
+	g.classes[id] = &eclass{nodes: []Node{n}}
+	for _, c := range n.Children {
+		cls := g.classes[c]
+		cls.parents = append(cls.parents, parent{node: n, class: id})
+	}
+	g.memo[k] = id
+	return id
+}
+
+// Union merges the e-classes of a and b and returns true if they were
+// separate. The congruence invariant is restored by the next Rebuild.
+func (g *EGraph) Union(a, b ClassID) bool {
+	ra, rb := g.dsu.Find(a), g.dsu.Find(b)
+	if ra == rb {
+		return false
+	}
+	g.dsu.Union(ra, rb)
+	r := g.dsu.Find(ra)
+	absorbed := ra
+	if r == ra {
+		absorbed = rb
+	}
+	keep, gone := g.classes[r], g.classes[absorbed]
+	keep.nodes = append(keep.nodes, gone.nodes...)
+	keep.parents = append(keep.parents, gone.parents...)
+	delete(g.classes, absorbed)
+	g.pending = append(g.pending, r)
+	return true
+}
+
+// Rebuild restores the congruence invariant after unions: e-nodes that
+// have become equal are merged into one, and their e-classes are united,
+// until no more e-classes merge.
+func (g *EGraph) Rebuild() {
+	if len(g.pending) == 0 {
+		return
+	}
+	for len(g.pending) > 0 {
+		todo := make(map[ClassID]struct{}, len(g.pending))
+		for _, c := range g.pending {
+			todo[g.dsu.Find(c)] = struct{}{}
+		}
+		g.pending = nil
+		for _, c := range slices.Sorted(maps.Keys(todo)) {
+			g.repair(c)
+		}
+	}
+
+	// canonicalize and deduplicate the e-nodes of every e-class
+	for _, cls := range g.classes {
+		seen := make(map[string]struct{}, len(cls.nodes))
+		nodes := cls.nodes[:0]
+		for _, n := range cls.nodes {
+			n = g.canonical(n)
+			if _, ok := seen[n.key()]; !ok {
+				seen[n.key()] = struct{}{}
+				nodes = append(nodes, n)
+			}
+		}
+		cls.nodes = nodes
+	}
+}
+
+// repair re-canonicalizes the parents of the e-class c, uniting the
+// e-classes of parents that have become equal.
+func (g *EGraph) repair(c ClassID) {
+	cls, ok := g.classes[g.dsu.Find(c)]
+	if !ok {
+		return
+	}
+	parents := cls.parents
+	cls.parents = nil
+	for _, p := range parents {
+		delete(g.memo, p.node.key())
+	}
+
+	seen := make(map[string]int, len(parents))
+	var repaired []parent
+	for _, p := range parents {
+		n := g.canonical(p.node)
+		k := n.key()
+		if id, ok := g.memo[k]; ok {
+			g.Union(id, p.class)
+		}
+		g.memo[k] = g.dsu.Find(p.class)
+		if i, ok := seen[k]; ok {
+			g.Union(repaired[i].class, p.class)
+			continue
+		}
+		seen[k] = len(repaired)
+		repaired = append(repaired, parent{node: n, class: p.class})
+	}
+
+	cls = g.classes[g.dsu.Find(c)]
+	cls.parents = append(cls.parents, repaired...)
+}
@@ -0,0 +1,25 @@
+package egraph
+
+import "fmt"
+
+// Example simplifies an expression by equality saturation and extracts
+// the smallest equivalent expression.
+func Example() {
+	rules := []*Rule{
+		MustRule("comm-mul", "(* ?a ?b)", "(* ?b ?a)"),
+		MustRule("mul-1", "(* ?a 1)", "?a"),
+		MustRule("div-self", "(/ (* ?a ?b) ?b)", "?a"),
+	}
+
+	g := New()
+	root := g.AddExpr(MustParse("(/ (* 1 (* x y)) x)"))
+	report := g.Run(rules)
+	best, cost := g.Extract(root, AstSize)
+
+	fmt.Println(report.Stop)
+	fmt.Println(best, cost)
+
+	// Output:
+	// saturated
+	// y 1
+}
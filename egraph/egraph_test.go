@@ -0,0 +1,108 @@
+package egraph
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/arunksaha/gdsu/congruence"
+)
+
+func TestAddHashConses(t *testing.T) {
+	g := New()
+	a, b := g.Add("a"), g.Add("b")
+	fa := g.Add("f", a)
+	if g.Add("f", a) != fa || g.Add("a") != a {
+		t.Fatal("equal e-nodes got different e-classes")
+	}
+	if g.Add("f", b) == fa {
+		t.Fatal("f(a) and f(b) share an e-class")
+	}
+	if g.ClassCount() != 4 || g.NodeCount() != 4 {
+		t.Fatalf("got %d classes and %d nodes, want 4 and 4", g.ClassCount(), g.NodeCount())
+	}
+}
+
+func TestRebuildIsDeferred(t *testing.T) {
+	g := New()
+	a, b := g.Add("a"), g.Add("b")
+	fa, fb := g.Add("f", a), g.Add("f", b)
+	ffa, ffb := g.Add("f", fa), g.Add("f", fb)
+
+	if !g.Union(a, b) || g.Union(b, a) {
+		t.Fatal("Union reported wrong merge results")
+	}
+	if g.Equivalent(fa, fb) {
+		t.Fatal("congruence restored before Rebuild")
+	}
+	g.Rebuild()
+	if !g.Equivalent(fa, fb) || !g.Equivalent(ffa, ffb) {
+		t.Fatal("congruence not restored by Rebuild")
+	}
+	if g.ClassCount() != 3 || g.NodeCount() != 4 {
+		t.Fatalf("got %d classes and %d nodes, want 3 and 4", g.ClassCount(), g.NodeCount())
+	}
+
+	// hash-consing sees the merged e-nodes
+	if g.Find(g.Add("f", b)) != g.Find(fa) {
+		t.Fatal("f(b) not found in the e-class of f(a)")
+	}
+}
+
+// TestRebuildMatchesCongruence builds the same random terms in an e-graph
+// and a congruence.Closure, unites random pairs in both, and compares
+// every pair of terms after each Rebuild.
+func TestRebuildMatchesCongruence(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 30; trial++ {
+		g, c := New(), congruence.New()
+		var ids []ClassID
+		var terms []congruence.Term
+		for _, name := range []string{"a", "b", "c", "d"} {
+			ids = append(ids, g.Add(name))
+			terms = append(terms, c.Const(name))
+		}
+		for step := 0; step < 60; step++ {
+			switch rng.Intn(4) {
+			case 0:
+				i, j := rng.Intn(len(ids)), rng.Intn(len(ids))
+				g.Union(ids[i], ids[j])
+				c.Assert(terms[i], terms[j])
+			case 1:
+				g.Rebuild()
+				for i := range ids {
+					for j := range ids {
+						if g.Equivalent(ids[i], ids[j]) != c.AreEqual(terms[i], terms[j]) {
+							t.Fatalf("trial %d step %d: terms %d and %d disagree", trial, step, i, j)
+						}
+					}
+				}
+			default:
+				fn := []string{"f", "g"}[rng.Intn(2)]
+				var args []int
+				for k := 0; k < 1+rng.Intn(2); k++ {
+					args = append(args, rng.Intn(len(ids)))
+				}
+				children := make([]ClassID, len(args))
+				subterms := make([]congruence.Term, len(args))
+				for k, a := range args {
+					children[k], subterms[k] = ids[a], terms[a]
+				}
+				ids = append(ids, g.Add(fn, children...))
+				terms = append(terms, c.App(fn, subterms...))
+			}
+		}
+		g.Rebuild()
+		if want := len(c.Classes()); g.ClassCount() != want {
+			t.Fatalf("trial %d: got %d e-classes, want %d", trial, g.ClassCount(), want)
+		}
+	}
+}
+
+func TestAddPanicsOnUnknownClass(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Add did not panic")
+		}
+	}()
+	New().Add("f", 0)
+}
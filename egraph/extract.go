@@ -0,0 +1,104 @@
+package egraph
+
+import (
+	"maps"
+	"math"
+	"slices"
+)
+
+// CostFunc returns the cost of an e-node with operator op whose children
+// have the given costs. For extraction to be well defined, the cost of an
+// e-node must exceed the cost of each of its children.
+type CostFunc func(op string, children []float64) float64
+
+// AstSize counts the operators of an expression.
+func AstSize(op string, children []float64) float64 {
+	cost := 1.0
+	for _, c := range children {
+		cost += c
+	}
+	return cost
+}
+
+// AstDepth measures the depth of an expression.
+func AstDepth(op string, children []float64) float64 {
+	depth := 0.0
+	for _, c := range children {
+		depth = max(depth, c)
+	}
+	return depth + 1
+}
+
+// Extract returns the cheapest expression represented by the e-class c
+// under cost, and its cost; a nil cost means AstSize. Among e-nodes of equal
+// cost, the first one added wins. It rebuilds the e-graph first if needed.
+//
+// The costs of all e-classes are computed together by iterating to a fixed
+// point, so extracting from several e-classes is best done with Extractor.
+// Panics if c is not an e-class of g.
+func (g *EGraph) Extract(c ClassID, cost CostFunc) (*Expr, float64) {
+	return g.Extractor(cost)(c)
+}
+
+// Extractor computes the cheapest expression of every e-class under cost
+// and returns a function that extracts them, like Extract. The function
+// reflects the e-graph at the time Extractor was called.
+func (g *EGraph) Extractor(cost CostFunc) func(ClassID) (*Expr, float64) {
+	if cost == nil {
+		cost = AstSize
+	}
+	g.Rebuild()
+
+	// relax the cost of every e-class until no e-node improves on it
+	type choice struct {
+		cost float64
+		node Node
+	}
+	best := make(map[ClassID]choice, len(g.classes))
+	ids := slices.Sorted(maps.Keys(g.classes))
+	for changed := true; changed; {
+		changed = false
+		for _, id := range ids {
+			for _, n := range g.classes[id].nodes {
+				costs := make([]float64, len(n.Children))
+				known := true
+				for i, ch := range n.Children {
+					b, ok := best[g.dsu.Find(ch)]
+					if !ok {
+						known = false
+						break
+					}
+					costs[i] = b.cost
+				}
+				if !known {
+					continue
+				}
+				k := cost(n.Op, costs)
+				if b, ok := best[id]; !ok || k < b.cost {
+					best[id] = choice{cost: k, node: n}
+					changed = true
+				}
+			}
+		}
+	}
+
+	var build func(id ClassID) *Expr
+	build = func(id ClassID) *Expr {
+		n := best[g.dsu.Find(id)].node
+		e := &Expr{Op: n.Op}
+		for _, ch := range n.Children {
+			e.Args = append(e.Args, build(ch))
+		}
+		return e
+	}
+	return func(c ClassID) (*Expr, float64) {
+		if c < 0 || c >= g.next {
+			panic("egraph.EGraph: unknown e-class in Extract")
+		}
+		b, ok := best[g.dsu.Find(c)]
+		if !ok {
+			return nil, math.Inf(1)
+		}
+		return build(c), b.cost
+	}
+}
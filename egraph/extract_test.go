@@ -0,0 +1,51 @@
+package egraph
+
+import "testing"
+
+func TestExtract(t *testing.T) {
+	g := New()
+	root := g.AddExpr(MustParse("(* (+ x 0) 2)"))
+	g.Run(arith)
+
+	// among equally small expressions, the first one added wins
+	e, cost := g.Extract(root, nil)
+	if e.String() != "(* x 2)" || cost != 3 {
+		t.Fatalf("got %v with cost %v", e, cost)
+	}
+
+	// a cost function that makes multiplication expensive prefers the sum
+	pricey := func(op string, children []float64) float64 {
+		c := AstSize(op, children)
+		if op == "*" {
+			c += 10
+		}
+		return c
+	}
+	if e, cost := g.Extract(root, pricey); e.String() != "(+ x x)" || cost != 3 {
+		t.Fatalf("got %v with cost %v", e, cost)
+	}
+}
+
+func TestExtractor(t *testing.T) {
+	g := New()
+	a := g.AddExpr(MustParse("(+ (+ x 0) 0)"))
+	b := g.AddExpr(MustParse("(* (* y 1) (+ z 0))"))
+	g.Run(arith)
+
+	extract := g.Extractor(AstDepth)
+	if e, depth := extract(a); e.String() != "x" || depth != 1 {
+		t.Fatalf("got %v with depth %v", e, depth)
+	}
+	if e, depth := extract(b); depth != 2 || (e.String() != "(* y z)" && e.String() != "(* z y)") {
+		t.Fatalf("got %v with depth %v", e, depth)
+	}
+}
+
+func TestExtractPanicsOnUnknownClass(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Extract did not panic")
+		}
+	}()
+	New().Extract(3, nil)
+}
@@ -0,0 +1,363 @@
+package egraph
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Expr is an expression tree, written as an s-expression such as
+// (+ (* a 2) 0). Constants and variables of the expression language are
+// operators without arguments.
+//
+// In patterns, a leaf whose operator starts with '?', such as ?x, is a
+// pattern variable that matches any e-class.
+type Expr struct {
+	Op   string
+	Args []*Expr
+}
+
+// isVar reports whether e is a pattern variable.
+func (e *Expr) isVar() bool {
+	return len(e.Args) == 0 && strings.HasPrefix(e.Op, "?")
+}
+
+// String returns e as an s-expression.
+func (e *Expr) String() string {
+	var b strings.Builder
+	e.format(&b)
+	return b.String()
+}
+
+// format writes e to b.
+func (e *Expr) format(b *strings.Builder) {
+	if len(e.Args) == 0 {
+		b.WriteString(e.Op)
+		return
+	}
+	b.WriteByte('(')
+	b.WriteString(e.Op)
+	for _, a := range e.Args {
+		b.WriteByte(' ')
+		a.format(b)
+	}
+	b.WriteByte(')')
+}
+
+// vars appends the pattern variables of e to vs in order of first
+// occurrence.
+func (e *Expr) vars(vs []string) []string {
+	if e.isVar() {
+		if !slices.Contains(vs, e.Op) {
+			vs = append(vs, e.Op)
+		}
+		return vs
+	}
+	for _, a := range e.Args {
+		vs = a.vars(vs)
+	}
+	return vs
+}
+
+// Parse parses an s-expression: an atom, or an operator followed by its
+// arguments in parentheses.
+func Parse(s string) (*Expr, error) {
+	p := &parser{tokens: tokenize(s)}
+	e, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("egraph: unexpected %q after expression in %q", p.tokens[p.pos], s)
+	}
+	return e, nil
+}
+
+// MustParse is like Parse but panics if s cannot be parsed.
+func MustParse(s string) *Expr {
+	e, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// tokenize splits s into parentheses and atoms.
+func tokenize(s string) []string {
+	s = strings.ReplaceAll(s, "(", " ( ")
+	s = strings.ReplaceAll(s, ")", " ) ")
+	return strings.Fields(s)
+}
+
+// parser is a recursive-descent parser over tokens.
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+// expr parses the expression starting at the current token.
+func (p *parser) expr() (*Expr, error) {
+	if p.pos == len(p.tokens) {
+		return nil, errors.New("egraph: unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok {
+	case ")":
+		return nil, errors.New("egraph: unexpected )")
+	case "(":
+		if p.pos == len(p.tokens) || p.tokens[p.pos] == "(" || p.tokens[p.pos] == ")" {
+			return nil, errors.New("egraph: expected operator after (")
+		}
+		e := &Expr{Op: p.tokens[p.pos]}
+		p.pos++
+		for p.pos < len(p.tokens) && p.tokens[p.pos] != ")" {
+			a, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			e.Args = append(e.Args, a)
+		}
+		if p.pos == len(p.tokens) {
+			return nil, errors.New("egraph: missing )")
+		}
+		p.pos++
+		if len(e.Args) == 0 {
+			return nil, fmt.Errorf("egraph: operator %s without arguments in parentheses", e.Op)
+		}
+		if strings.HasPrefix(e.Op, "?") {
+			return nil, fmt.Errorf("egraph: pattern variable %s applied to arguments", e.Op)
+		}
+		return e, nil
+	}
+	return &Expr{Op: tok}, nil
+}
+
+// AddExpr adds every subexpression of e to the e-graph and returns the
+// e-class of e. Pattern variables are added as ordinary constants.
+func (g *EGraph) AddExpr(e *Expr) ClassID {
+	children := make([]ClassID, len(e.Args))
+	for i, a := range e.Args {
+		children[i] = g.AddExpr(a)
+	}
+	return g.Add(e.Op, children...)
+}
+
+// Subst maps pattern variables to e-classes.
+type Subst map[string]ClassID
+
+// Match is an occurrence of a pattern: the e-class it matches, and the
+// e-classes its variables are bound to.
+type Match struct {
+	Class ClassID
+	Subst Subst
+}
+
+// Search returns every match of the pattern p in the e-graph, ordered by
+// e-class. It rebuilds the e-graph first if needed.
+func (g *EGraph) Search(p *Expr) []Match {
+	g.Rebuild()
+	var matches []Match
+	for _, c := range slices.Sorted(maps.Keys(g.classes)) {
+		for _, s := range g.match(p, c, Subst{}) {
+			matches = append(matches, Match{Class: c, Subst: s})
+		}
+	}
+	return matches
+}
+
+// match returns the extensions of s under which p matches the e-class c.
+func (g *EGraph) match(p *Expr, c ClassID, s Subst) []Subst {
+	c = g.dsu.Find(c)
+	if p.isVar() {
+		if bound, ok := s[p.Op]; ok {
+			if g.dsu.Find(bound) == c {
+				return []Subst{s}
+			}
+			return nil
+		}
+		ext := maps.Clone(s)
+		ext[p.Op] = c
+		return []Subst{ext}
+	}
+
+	var out []Subst
+	for _, n := range g.classes[c].nodes {
+		if n.Op != p.Op || len(n.Children) != len(p.Args) {
+			continue
+		}
+		substs := []Subst{s}
+		for i, a := range p.Args {
+			var next []Subst
+			for _, t := range substs {
+				next = append(next, g.match(a, n.Children[i], t)...)
+			}
+			substs = next
+		}
+		out = append(out, substs...)
+	}
+	return out
+}
+
+// Instantiate adds the pattern p with its variables replaced according to s
+// and returns its e-class.
+// Panics if a variable of p is not bound by s.
+func (g *EGraph) Instantiate(p *Expr, s Subst) ClassID {
+	if p.isVar() {
+		c, ok := s[p.Op]
+		if !ok {
+			panic("egraph.EGraph: unbound pattern variable in Instantiate")
+		}
+		return g.dsu.Find(c)
+	}
+	children := make([]ClassID, len(p.Args))
+	for i, a := range p.Args {
+		children[i] = g.Instantiate(a, s)
+	}
+	return g.Add(p.Op, children...)
+}
+
+// Rule is a rewrite rule: every e-class that matches LHS is united with the
+// instantiation of RHS.
+type Rule struct {
+	Name     string
+	LHS, RHS *Expr
+}
+
+// NewRule parses a rewrite rule. The left-hand side must not be a bare
+// variable, and every variable of the right-hand side must occur in the
+// left-hand side.
+func NewRule(name, lhs, rhs string) (*Rule, error) {
+	l, err := Parse(lhs)
+	if err != nil {
+		return nil, fmt.Errorf("egraph: rule %s: %w", name, err)
+	}
+	r, err := Parse(rhs)
+	if err != nil {
+		return nil, fmt.Errorf("egraph: rule %s: %w", name, err)
+	}
+	if l.isVar() {
+		return nil, fmt.Errorf("egraph: rule %s: left-hand side is a variable", name)
+	}
+	bound := l.vars(nil)
+	for _, v := range r.vars(nil) {
+		if !slices.Contains(bound, v) {
+			return nil, fmt.Errorf("egraph: rule %s: variable %s not bound by left-hand side", name, v)
+		}
+	}
+	return &Rule{Name: name, LHS: l, RHS: r}, nil
+}
+
+// MustRule is like NewRule but panics if the rule is invalid.
+func MustRule(name, lhs, rhs string) *Rule {
+	r, err := NewRule(name, lhs, rhs)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// String returns the rule as name: lhs => rhs.
+func (r *Rule) String() string {
+	return r.Name + ": " + r.LHS.String() + " => " + r.RHS.String()
+}
+
+// StopReason tells why Run stopped.
+type StopReason int
+
+const (
+	// Saturated means that the rules no longer change the e-graph.
+	Saturated StopReason = iota
+	// IterationLimit means that Run performed the maximum number of
+	// iterations.
+	IterationLimit
+	// NodeLimit means that the e-graph grew beyond the maximum number of
+	// e-nodes.
+	NodeLimit
+)
+
+// String returns the name of the stop reason.
+func (s StopReason) String() string {
+	switch s {
+	case Saturated:
+		return "saturated"
+	case IterationLimit:
+		return "iteration limit"
+	case NodeLimit:
+		return "node limit"
+	}
+	return fmt.Sprintf("StopReason(%d)", int(s))
+}
+
+// Report summarizes a Run.
+type Report struct {
+	Iterations int
+	Stop       StopReason
+
+	// Classes and Nodes are the numbers of e-classes and e-nodes when Run
+	// stopped.
+	Classes, Nodes int
+}
+
+// Option configures Run.
+type Option func(*options)
+
+type options struct {
+	iterations int
+	nodes      int
+}
+
+// Iterations limits Run to n iterations. The default is 30.
+func Iterations(n int) Option {
+	return func(o *options) { o.iterations = n }
+}
+
+// Nodes makes Run stop once the e-graph has more than n e-nodes. The
+// default is 10,000.
+func Nodes(n int) Option {
+	return func(o *options) { o.nodes = n }
+}
+
+// Run performs equality saturation: every iteration searches for all
+// matches of all rules, then applies them and rebuilds the e-graph, so the
+// order of the rules does not matter. Run stops when an iteration changes
+// nothing, or when a limit is reached.
+func (g *EGraph) Run(rules []*Rule, opts ...Option) Report {
+	o := options{iterations: 30, nodes: 10_000}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	report := func(iterations int, stop StopReason) Report {
+		return Report{Iterations: iterations, Stop: stop, Classes: g.ClassCount(), Nodes: g.NodeCount()}
+	}
+	g.Rebuild()
+	for i := 0; i < o.iterations; i++ {
+		if g.NodeCount() > o.nodes {
+			return report(i, NodeLimit)
+		}
+		matches := make([][]Match, len(rules))
+		for j, r := range rules {
+			matches[j] = g.Search(r.LHS)
+		}
+
+		changed := false
+		for j, r := range rules {
+			for _, m := range matches[j] {
+				if g.Union(m.Class, g.Instantiate(r.RHS, m.Subst)) {
+					changed = true
+				}
+			}
+		}
+		g.Rebuild()
+		if !changed {
+			return report(i+1, Saturated)
+		}
+	}
+	if g.NodeCount() > o.nodes {
+		return report(o.iterations, NodeLimit)
+	}
+	return report(o.iterations, IterationLimit)
+}
@@ -0,0 +1,109 @@
+package egraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, s := range []string{"x", "(+ a b)", "(f (g ?x) ?y 0)"} {
+		e, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", s, err)
+		}
+		if e.String() != s {
+			t.Fatalf("Parse(%q).String() = %q", s, e.String())
+		}
+	}
+	for _, s := range []string{"", "(", ")", "(f", "()", "(f)", "((f) a)", "a b", "(?x a)"} {
+		if _, err := Parse(s); err == nil {
+			t.Fatalf("Parse(%q) succeeded", s)
+		}
+	}
+}
+
+func TestNewRuleErrors(t *testing.T) {
+	for _, r := range [][2]string{{"?x", "(f ?x)"}, {"(f ?x)", "?y"}, {"(f", "a"}, {"a", "("}} {
+		_, err := NewRule("bad", r[0], r[1])
+		if err == nil || !strings.HasPrefix(err.Error(), "egraph: rule bad: ") {
+			t.Fatalf("NewRule(%q, %q) = %v", r[0], r[1], err)
+		}
+	}
+	r := MustRule("comm", "(+ ?a ?b)", "(+ ?b ?a)")
+	if r.String() != "comm: (+ ?a ?b) => (+ ?b ?a)" {
+		t.Fatalf("got %q", r.String())
+	}
+}
+
+func TestSearch(t *testing.T) {
+	g := New()
+	root := g.AddExpr(MustParse("(+ (* a a) (* a b))"))
+	matches := g.Search(MustParse("(* ?x ?x)"))
+	if len(matches) != 1 || matches[0].Subst["?x"] != g.Add("a") {
+		t.Fatalf("got %v", matches)
+	}
+	if got := len(g.Search(MustParse("(* ?x ?y)"))); got != 2 {
+		t.Fatalf("got %d matches of (* ?x ?y), want 2", got)
+	}
+
+	// after a = b, both products match (* ?x ?x)
+	g.Union(g.Add("a"), g.Add("b"))
+	if got := len(g.Search(MustParse("(* ?x ?x)"))); got != 1 {
+		t.Fatalf("got %d matches after merging, want 1 merged e-class", got)
+	}
+	if m := g.Search(MustParse("(+ ?p ?p)")); len(m) != 1 || m[0].Class != g.Find(root) {
+		t.Fatalf("got %v", m)
+	}
+}
+
+var arith = []*Rule{
+	MustRule("comm-add", "(+ ?a ?b)", "(+ ?b ?a)"),
+	MustRule("comm-mul", "(* ?a ?b)", "(* ?b ?a)"),
+	MustRule("add-0", "(+ ?a 0)", "?a"),
+	MustRule("mul-1", "(* ?a 1)", "?a"),
+	MustRule("mul-0", "(* ?a 0)", "0"),
+	MustRule("mul-2", "(* ?a 2)", "(+ ?a ?a)"),
+}
+
+func TestRunSaturates(t *testing.T) {
+	g := New()
+	root := g.AddExpr(MustParse("(+ 0 (* (* 1 x) (+ y 0)))"))
+	rep := g.Run(arith)
+	if rep.Stop != Saturated {
+		t.Fatalf("stopped by %v after %d iterations", rep.Stop, rep.Iterations)
+	}
+	if !g.Equivalent(root, g.AddExpr(MustParse("(* y x)"))) {
+		t.Fatal("root not equivalent to (* y x)")
+	}
+	if rep.Nodes != g.NodeCount() || rep.Classes != g.ClassCount() {
+		t.Fatalf("report %+v does not match the e-graph", rep)
+	}
+
+	// a second run changes nothing
+	if rep := g.Run(arith); rep.Stop != Saturated || rep.Iterations != 1 {
+		t.Fatalf("second run: %+v", rep)
+	}
+}
+
+func TestRunLimits(t *testing.T) {
+	assoc := []*Rule{
+		MustRule("comm", "(+ ?a ?b)", "(+ ?b ?a)"),
+		MustRule("assoc", "(+ ?a (+ ?b ?c))", "(+ (+ ?a ?b) ?c)"),
+	}
+	e := MustParse("(+ a (+ b (+ c (+ d (+ e (+ f g))))))")
+
+	g := New()
+	g.AddExpr(e)
+	if rep := g.Run(assoc, Iterations(2)); rep.Stop != IterationLimit || rep.Iterations != 2 {
+		t.Fatalf("got %+v", rep)
+	}
+
+	g = New()
+	g.AddExpr(e)
+	if rep := g.Run(assoc, Nodes(50)); rep.Stop != NodeLimit || rep.Nodes <= 50 {
+		t.Fatalf("got %+v", rep)
+	}
+	if NodeLimit.String() != "node limit" {
+		t.Fatalf("got %q", NodeLimit.String())
+	}
+}
@@ -26,3 +26,24 @@ type DSU[T comparable] interface {
 	// Groups returns a mapping of each root to all elements in its set.
 	Groups() map[T][]T
 }
+
+// BatchDSU is a DSU that also supports batch operations.
+//
+// A single batch call avoids the per-element overhead of calling the DSU
+// methods through an interface, and lets implementations reorder the work
+// internally.
+type BatchDSU[T comparable] interface {
+	DSU[T]
+
+	// UnionAll merges the sets containing all of xs, returning the number of merges.
+	UnionAll(xs ...T) int
+
+	// UnionPairs merges the sets containing each pair, returning the number of merges.
+	UnionPairs(pairs [][2]T) int
+
+	// FindMany appends the root of each element of xs to dst, in order.
+	FindMany(dst, xs []T) []T
+
+	// ConnectedMany appends to dst whether the elements of each pair are connected.
+	ConnectedMany(dst []bool, pairs [][2]T) []bool
+}
@@ -15,3 +15,16 @@ func TestInterfaceCompiles(t *testing.T) {
 	// This is a compile-time assertion that fakeDSU[int] satisfies DSU[int].
 	var _ DSU[int] = &fakeDSU[int]{}
 }
+
+// fakeBatchDSU extends fakeDSU with trivial batch operations.
+type fakeBatchDSU[T comparable] struct{ fakeDSU[T] }
+
+func (f *fakeBatchDSU[T]) UnionAll(xs ...T) int                      { return 0 }
+func (f *fakeBatchDSU[T]) UnionPairs(pairs [][2]T) int               { return 0 }
+func (f *fakeBatchDSU[T]) FindMany(dst, xs []T) []T                  { return append(dst, xs...) }
+func (f *fakeBatchDSU[T]) ConnectedMany(dst []bool, p [][2]T) []bool { return dst }
+
+func TestBatchInterfaceCompiles(t *testing.T) {
+	// This is a compile-time assertion that fakeBatchDSU[int] satisfies BatchDSU[int].
+	var _ BatchDSU[int] = &fakeBatchDSU[int]{}
+}
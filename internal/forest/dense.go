@@ -0,0 +1,110 @@
+package forest
+
+// Dense is a forest over the fixed range [0, n) with edge labels of type L,
+// stored in slices.
+type Dense[L any] struct {
+	// parent[x] is the parent of x, or -1 if x is the root of its tree,
+	// and label[x] is the label of the edge between the two.
+	parent []int
+	label  []L
+
+	// visited[x] == epoch marks the elements visited by the current Meet.
+	visited []int
+	epoch   int
+}
+
+// NewDense creates a forest of singleton trees [0, size).
+func NewDense[L any](size int) *Dense[L] {
+	if size < 0 {
+		size = 0
+	}
+	f := &Dense[L]{
+		parent:  make([]int, size),
+		label:   make([]L, size),
+		visited: make([]int, size),
+	}
+	for x := range f.parent {
+		f.parent[x] = -1
+	}
+	return f
+}
+
+// Parent returns the parent of x and the label of the edge between the two,
+// or false if x is the root of its tree.
+func (f *Dense[L]) Parent(x int) (int, L, bool) {
+	return f.parent[x], f.label[x], f.parent[x] >= 0
+}
+
+// Link makes x the root of its tree by reversing the edges on the path from
+// x to the old root, and then hangs it below y with the given label. x and
+// y must be in different trees.
+func (f *Dense[L]) Link(x, y int, label L) {
+	child := -1
+	var below L
+	for v := x; v >= 0; {
+		p, l := f.parent[v], f.label[v]
+		f.parent[v], f.label[v] = child, below
+		child, below = v, l
+		v = p
+	}
+	f.parent[x], f.label[x] = y, label
+}
+
+// Meet returns the nearest common ancestor c of x and y, which must be in
+// the same tree, and the elements on the paths from x and from y up to but
+// not including c, in order of climbing.
+func (f *Dense[L]) Meet(x, y int) (c int, fromX, fromY []int) {
+	f.epoch++
+	ends := [2]int{x, y}
+	paths := [2][]int{}
+	for i := 0; ; i = 1 - i {
+		v := ends[i]
+		if v < 0 {
+			continue
+		}
+		if f.visited[v] == f.epoch {
+			c = v
+			break
+		}
+		f.visited[v] = f.epoch
+		paths[i] = append(paths[i], v)
+		ends[i] = f.parent[v]
+	}
+
+	// the other end may have climbed past c before this one reached it
+	for i := range paths {
+		for k, v := range paths[i] {
+			if v == c {
+				paths[i] = paths[i][:k]
+				break
+			}
+		}
+	}
+	return c, paths[0], paths[1]
+}
+
+// Path returns the labels of the edges on the path from x to y, which must
+// be in the same tree, in order.
+func (f *Dense[L]) Path(x, y int) []L {
+	_, fromX, fromY := f.Meet(x, y)
+	path := make([]L, 0, len(fromX)+len(fromY))
+	for _, v := range fromX {
+		path = append(path, f.label[v])
+	}
+	for i := len(fromY) - 1; i >= 0; i-- {
+		path = append(path, f.label[fromY[i]])
+	}
+	return path
+}
+
+// Labels returns the labels of all edges, ordered by the element below
+// each edge.
+func (f *Dense[L]) Labels() []L {
+	var labels []L
+	for x, p := range f.parent {
+		if p >= 0 {
+			labels = append(labels, f.label[x])
+		}
+	}
+	return labels
+}
@@ -0,0 +1,143 @@
+// Package forest provides the spanning forest with labeled edges that
+// explains connections in sparse.Witness, compact.Witness and the packages
+// built like them: proof, parity and bridges.
+//
+// The forest is stored with parent pointers. Link reroots the tree of one
+// endpoint of a new edge at that endpoint and hangs it below the other;
+// callers keep the set sizes and link the smaller tree, so that a sequence
+// of links takes O(n log n) time. Meet climbs from two elements in turn
+// towards their nearest common ancestor and stops as soon as one reaches an
+// element the other has visited, so it takes time linear in the length of
+// the path between them, not in the depth of the tree.
+//
+// Map works with any comparable elements; Dense works with integers in a
+// fixed range and stores the forest in slices.
+package forest
+
+// Map is a forest over elements of type T with edge labels of type L.
+type Map[T comparable, L any] struct {
+	// parent maps every element other than the root of its tree to its
+	// parent, and label to the label of the edge between the two.
+	parent map[T]T
+	label  map[T]L
+
+	// canon maps a stored parent to the element that stands for it.
+	canon func(T) T
+}
+
+// NewMap creates an empty forest. If canon is not nil, every stored parent
+// p is read as canon(p); bridges uses this to climb between contracted
+// components.
+func NewMap[T comparable, L any](canon func(T) T) *Map[T, L] {
+	if canon == nil {
+		canon = func(x T) T { return x }
+	}
+	return &Map[T, L]{parent: make(map[T]T), label: make(map[T]L), canon: canon}
+}
+
+// Parent returns the parent of x and the label of the edge between the two,
+// or false if x is the root of its tree.
+func (f *Map[T, L]) Parent(x T) (T, L, bool) {
+	p, ok := f.parent[x]
+	if !ok {
+		var zero L
+		return p, zero, false
+	}
+	return f.canon(p), f.label[x], true
+}
+
+// Set makes p the parent of x with the given label, replacing the edge above
+// x, if any.
+func (f *Map[T, L]) Set(x, p T, label L) {
+	f.parent[x], f.label[x] = p, label
+}
+
+// Cut removes the edge above x, if any.
+func (f *Map[T, L]) Cut(x T) {
+	delete(f.parent, x)
+	delete(f.label, x)
+}
+
+// Link makes x the root of its tree by reversing the edges on the path from
+// x to the old root, and then hangs it below y with the given label. x and
+// y must be in different trees.
+func (f *Map[T, L]) Link(x, y T, label L) {
+	var child T
+	var below L
+	hasChild := false
+	for v := x; ; {
+		p, l, hasParent := f.Parent(v)
+		if hasChild {
+			f.Set(v, child, below)
+		} else {
+			f.Cut(v)
+		}
+		if !hasParent {
+			break
+		}
+		child, below, hasChild = v, l, true
+		v = p
+	}
+	f.Set(x, y, label)
+}
+
+// Meet returns the nearest common ancestor c of x and y, which must be in
+// the same tree, and the elements on the paths from x and from y up to but
+// not including c, in order of climbing.
+func (f *Map[T, L]) Meet(x, y T) (c T, fromX, fromY []T) {
+	visited := map[T]struct{}{}
+	ends := [2]T{x, y}
+	paths := [2][]T{}
+	var done [2]bool
+	for i := 0; ; i = 1 - i {
+		if done[i] {
+			continue
+		}
+		v := ends[i]
+		if _, ok := visited[v]; ok {
+			c = v
+			break
+		}
+		visited[v] = struct{}{}
+		paths[i] = append(paths[i], v)
+		if p, _, ok := f.Parent(v); ok {
+			ends[i] = p
+		} else {
+			done[i] = true
+		}
+	}
+
+	// the other end may have climbed past c before this one reached it
+	for i := range paths {
+		for k, v := range paths[i] {
+			if v == c {
+				paths[i] = paths[i][:k]
+				break
+			}
+		}
+	}
+	return c, paths[0], paths[1]
+}
+
+// Path returns the labels of the edges on the path from x to y, which must
+// be in the same tree, in order.
+func (f *Map[T, L]) Path(x, y T) []L {
+	_, fromX, fromY := f.Meet(x, y)
+	path := make([]L, 0, len(fromX)+len(fromY))
+	for _, v := range fromX {
+		path = append(path, f.label[v])
+	}
+	for i := len(fromY) - 1; i >= 0; i-- {
+		path = append(path, f.label[fromY[i]])
+	}
+	return path
+}
+
+// Labels returns the labels of all edges, in no particular order.
+func (f *Map[T, L]) Labels() []L {
+	labels := make([]L, 0, len(f.label))
+	for _, l := range f.label {
+		labels = append(labels, l)
+	}
+	return labels
+}
@@ -0,0 +1,75 @@
+package forest
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// TestForestsAgree links random edges in a Map and a Dense forest and
+// ensures both return the same valid paths.
+func TestForestsAgree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 200
+	m := NewMap[int, [2]int](nil)
+	d := NewDense[[2]int](n)
+
+	// tree[x] is the index of the tree of x, for the test only
+	tree := make([]int, n)
+	for x := range tree {
+		tree[x] = x
+	}
+	for i := 0; i < 4*n; i++ {
+		x, y := rng.Intn(n), rng.Intn(n)
+		if tree[x] != tree[y] {
+			e := [2]int{x, y}
+			m.Link(x, y, e)
+			d.Link(x, y, e)
+			old := tree[x]
+			for v := range tree {
+				if tree[v] == old {
+					tree[v] = tree[y]
+				}
+			}
+			continue
+		}
+
+		got, want := m.Path(x, y), d.Path(x, y)
+		if !slices.Equal(got, want) {
+			t.Fatalf("Map path %v, Dense path %v", got, want)
+		}
+		at := x
+		for _, e := range got {
+			switch at {
+			case e[0]:
+				at = e[1]
+			case e[1]:
+				at = e[0]
+			default:
+				t.Fatalf("path %v from %d is not a walk", got, x)
+			}
+		}
+		if at != y {
+			t.Fatalf("path %v from %d ends at %d, want %d", got, x, at, y)
+		}
+	}
+	if len(m.Labels()) != len(d.Labels()) {
+		t.Fatalf("Map has %d edges, Dense %d", len(m.Labels()), len(d.Labels()))
+	}
+}
+
+// TestMeetSiblingsDeep ensures Meet of two siblings deep in a long path
+// visits only a few elements.
+func TestMeetSiblingsDeep(t *testing.T) {
+	const n = 100_000
+	d := NewDense[int](n + 1)
+	for x := 1; x < n; x++ {
+		d.Link(x, x-1, x)
+	}
+	d.Link(n, n-2, n)
+
+	c, fromX, fromY := d.Meet(n-1, n)
+	if c != n-2 || len(fromX) != 1 || len(fromY) != 1 {
+		t.Fatalf("Meet = %d, %v, %v", c, fromX, fromY)
+	}
+}
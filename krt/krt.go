@@ -0,0 +1,189 @@
+// Package krt builds Kruskal reconstruction trees for answering online
+// bottleneck and threshold-connectivity queries.
+//
+// A Kruskal reconstruction tree over the vertices [0, n) has the vertices
+// as leaves and one internal node per union performed by Kruskal's
+// algorithm: when an edge of weight w merges two components, a new node
+// of weight w becomes the parent of the two components' tree roots. Node
+// weights therefore never decrease on the way up, and two vertices are
+// connected using only edges of weight <= w exactly when their highest
+// ancestors of weight <= w coincide. The weight of the lowest common
+// ancestor of two vertices is the minimax (bottleneck) edge weight on
+// the path between them in the minimum spanning forest.
+//
+// The tree is built with a compact.DSU and queried with binary lifting,
+// so every query takes O(log n) time.
+package krt
+
+import (
+	"cmp"
+	"math/bits"
+	"slices"
+
+	"github.com/arunksaha/gdsu/compact"
+	"github.com/arunksaha/gdsu/mst"
+)
+
+// Tree is a Kruskal reconstruction tree.
+type Tree[W cmp.Ordered] struct {
+	// n is the number of vertices; nodes [0, n) are the leaves and nodes
+	// [n, len(weight)) are the internal nodes in order of creation.
+	n int
+
+	// weight[v] is the weight of internal node v; unused for leaves.
+	weight []W
+
+	// depth[v] is the distance from v to the root of its tree.
+	depth []int
+
+	// up[j][v] is the 2^j-th ancestor of v, or the root if there is none.
+	up [][]int
+
+	// leaves[v] is the number of vertices in the subtree of v.
+	leaves []int
+}
+
+// Build returns the Kruskal reconstruction tree of the graph over the
+// vertices [0, n) with the given edges. Edges of equal weight are
+// considered in input order.
+// Panics if any edge has a vertex out of range.
+func Build[W cmp.Ordered](n int, edges []mst.Edge[int, W]) *Tree[W] {
+	if n < 0 {
+		n = 0
+	}
+	for _, e := range edges {
+		if e.U < 0 || e.U >= n || e.V < 0 || e.V >= n {
+			panic("krt: vertex out of range in Build")
+		}
+	}
+	sorted := slices.Clone(edges)
+	slices.SortStableFunc(sorted, func(a, b mst.Edge[int, W]) int { return cmp.Compare(a.Weight, b.Weight) })
+
+	// top[r] is the tree node of the component whose DSU root is r
+	dsu := compact.New(n)
+	top := make([]int, n)
+	for v := range top {
+		top[v] = v
+	}
+	parent := make([]int, n, 2*n)
+	weight := make([]W, n, 2*n)
+	for v := range parent {
+		parent[v] = v
+	}
+	for _, e := range sorted {
+		ru, rv := dsu.Find(e.U), dsu.Find(e.V)
+		if ru == rv {
+			continue
+		}
+		node := len(parent)
+		parent = append(parent, node)
+		weight = append(weight, e.Weight)
+		parent[top[ru]], parent[top[rv]] = node, node
+		dsu.Union(ru, rv)
+		top[dsu.Find(ru)] = node
+	}
+
+	// parents are created after their children, so a reverse scan sees
+	// every parent before its children
+	size := len(parent)
+	depth := make([]int, size)
+	leaves := make([]int, size)
+	for v := size - 1; v >= 0; v-- {
+		if p := parent[v]; p != v {
+			depth[v] = depth[p] + 1
+		}
+	}
+	for v := 0; v < size; v++ {
+		if v < n {
+			leaves[v] = 1
+		}
+		if p := parent[v]; p != v {
+			leaves[p] += leaves[v]
+		}
+	}
+
+	levels := max(1, bits.Len(uint(size)))
+	up := make([][]int, levels)
+	up[0] = parent
+	for j := 1; j < levels; j++ {
+		up[j] = make([]int, size)
+		for v := range up[j] {
+			up[j][v] = up[j-1][up[j-1][v]]
+		}
+	}
+
+	return &Tree[W]{n: n, weight: weight, depth: depth, up: up, leaves: leaves}
+}
+
+// Len returns the number of vertices.
+func (t *Tree[W]) Len() int {
+	return t.n
+}
+
+// boundsCheck ensures x is a vertex.
+func (t *Tree[W]) boundsCheck(x int) bool {
+	return 0 <= x && x < t.n
+}
+
+// climb returns the highest ancestor of vertex x whose weight is <= w,
+// or x itself if there is none.
+func (t *Tree[W]) climb(x int, w W) int {
+	for j := len(t.up) - 1; j >= 0; j-- {
+		if a := t.up[j][x]; a != x && t.weight[a] <= w {
+			x = a
+		}
+	}
+	return x
+}
+
+// Connected reports whether x and y are connected using only edges of
+// weight <= w. Panics if x or y are out of range.
+func (t *Tree[W]) Connected(x, y int, w W) bool {
+	if !t.boundsCheck(x) || !t.boundsCheck(y) {
+		panic("krt: vertex out of range in Connected")
+	}
+	return t.climb(x, w) == t.climb(y, w)
+}
+
+// Size returns the number of vertices connected to x using only edges of
+// weight <= w, including x itself. Panics if x is out of range.
+func (t *Tree[W]) Size(x int, w W) int {
+	if !t.boundsCheck(x) {
+		panic("krt: vertex out of range in Size")
+	}
+	return t.leaves[t.climb(x, w)]
+}
+
+// Bottleneck returns the minimax edge weight on paths between x and y:
+// the smallest w such that x and y are connected using only edges of
+// weight <= w. It returns false if x and y are not connected at all, and
+// the zero weight if x == y. Panics if x or y are out of range.
+func (t *Tree[W]) Bottleneck(x, y int) (W, bool) {
+	var zero W
+	if !t.boundsCheck(x) || !t.boundsCheck(y) {
+		panic("krt: vertex out of range in Bottleneck")
+	}
+	if x == y {
+		return zero, true
+	}
+	if t.depth[x] < t.depth[y] {
+		x, y = y, x
+	}
+	for j := len(t.up) - 1; j >= 0; j-- {
+		if t.depth[x]-(1<<j) >= t.depth[y] {
+			x = t.up[j][x]
+		}
+	}
+	for j := len(t.up) - 1; j >= 0; j-- {
+		if t.up[j][x] != t.up[j][y] {
+			x, y = t.up[j][x], t.up[j][y]
+		}
+	}
+	if x != y {
+		x, y = t.up[0][x], t.up[0][y]
+	}
+	if x != y {
+		return zero, false
+	}
+	return t.weight[x], true
+}
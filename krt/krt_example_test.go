@@ -0,0 +1,27 @@
+package krt
+
+import (
+	"fmt"
+
+	"github.com/arunksaha/gdsu/mst"
+)
+
+// Example answers threshold-connectivity and bottleneck queries over a
+// small road network whose edge weights are road heights.
+func Example() {
+	roads := []mst.Edge[int, int]{
+		{U: 0, V: 1, Weight: 3}, {U: 1, V: 2, Weight: 5}, {U: 0, V: 2, Weight: 8}, {U: 2, V: 3, Weight: 4},
+	}
+	tree := Build(4, roads)
+
+	fmt.Println(tree.Connected(0, 3, 4)) // only roads of height <= 4
+	fmt.Println(tree.Connected(0, 3, 5))
+	fmt.Println(tree.Bottleneck(0, 3))
+	fmt.Println(tree.Size(3, 4))
+
+	// Output:
+	// false
+	// true
+	// 5 true
+	// 2
+}
@@ -0,0 +1,107 @@
+package krt
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/arunksaha/gdsu/compact"
+	"github.com/arunksaha/gdsu/mst"
+)
+
+// thresholdDSU returns a DSU over [0, n) with every edge of weight <= w.
+func thresholdDSU(n int, edges []mst.Edge[int, int], w int) *compact.DSU {
+	dsu := compact.New(n)
+	for _, e := range edges {
+		if e.Weight <= w {
+			dsu.Union(e.U, e.V)
+		}
+	}
+	return dsu
+}
+
+// TestTreeMatchesDSU cross-checks every query against a compact.DSU built
+// from the edges under each threshold.
+func TestTreeMatchesDSU(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n, maxWeight = 60, 20
+	edges := make([]mst.Edge[int, int], 70)
+	for i := range edges {
+		edges[i] = mst.Edge[int, int]{U: rng.Intn(n), V: rng.Intn(n), Weight: rng.Intn(maxWeight)}
+	}
+	tree := Build(n, edges)
+
+	dsus := make([]*compact.DSU, maxWeight)
+	for w := range dsus {
+		dsus[w] = thresholdDSU(n, edges, w)
+	}
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			for w, dsu := range dsus {
+				if got, want := tree.Connected(x, y, w), dsu.Connected(x, y); got != want {
+					t.Fatalf("Connected(%d, %d, %d) = %v, want %v", x, y, w, got, want)
+				}
+			}
+
+			// the bottleneck is the smallest threshold that connects x and y
+			want, wantOK := 0, false
+			for w, dsu := range dsus {
+				if dsu.Connected(x, y) {
+					want, wantOK = w, true
+					break
+				}
+			}
+			if x == y {
+				want = 0
+			}
+			if got, ok := tree.Bottleneck(x, y); got != want || ok != wantOK {
+				t.Fatalf("Bottleneck(%d, %d) = %d, %v, want %d, %v", x, y, got, ok, want, wantOK)
+			}
+		}
+		for w, dsu := range dsus {
+			if got, want := tree.Size(x, w), len(dsu.Groups()[dsu.Find(x)]); got != want {
+				t.Fatalf("Size(%d, %d) = %d, want %d", x, w, got, want)
+			}
+		}
+	}
+}
+
+// TestTreeFloatWeights checks a small hand-built example with float weights.
+func TestTreeFloatWeights(t *testing.T) {
+	edges := []mst.Edge[int, float64]{
+		{U: 0, V: 1, Weight: 2.5}, {U: 1, V: 2, Weight: 0.5}, {U: 0, V: 2, Weight: 9}, {U: 3, V: 4, Weight: 1},
+	}
+	tree := Build(5, edges)
+
+	if tree.Len() != 5 {
+		t.Fatalf("expected 5 vertices, got %d", tree.Len())
+	}
+	if !tree.Connected(0, 2, 2.5) || tree.Connected(0, 2, 2.4) {
+		t.Fatal("0 and 2 should be connected exactly from threshold 2.5")
+	}
+	if w, ok := tree.Bottleneck(2, 0); !ok || w != 2.5 {
+		t.Fatalf("Bottleneck(2, 0) = %v, %v, want 2.5, true", w, ok)
+	}
+	if _, ok := tree.Bottleneck(0, 4); ok {
+		t.Fatal("0 and 4 should not be connected")
+	}
+}
+
+// TestTreeOutOfRange ensures queries panic on vertices outside [0, n).
+func TestTreeOutOfRange(t *testing.T) {
+	tree := Build(3, []mst.Edge[int, int]{{U: 0, V: 1, Weight: 1}})
+	for name, query := range map[string]func(){
+		"Connected":  func() { tree.Connected(0, 3, 1) },
+		"Size":       func() { tree.Size(-1, 1) },
+		"Bottleneck": func() { tree.Bottleneck(5, 0) },
+		"Build":      func() { Build(2, []mst.Edge[int, int]{{U: 0, V: 2, Weight: 1}}) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("expected panic on out-of-range vertex in %s, got none", name)
+				}
+			}()
+			query()
+		}()
+	}
+}
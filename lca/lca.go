@@ -0,0 +1,184 @@
+// Package lca answers batches of lowest-common-ancestor queries over static
+// rooted trees with Tarjan's offline algorithm.
+//
+// Nodes may be of any comparable type; they are numbered internally and
+// tracked with a compact.DSU. The whole batch is answered during a single
+// depth-first traversal in near-linear time, O((n + q) α(n)). The
+// traversal is iterative, so arbitrarily deep trees do not overflow the
+// goroutine stack.
+package lca
+
+import "github.com/arunksaha/gdsu/compact"
+
+// Tree is a static rooted forest.
+type Tree[T comparable] struct {
+	// nodes[i] is the node numbered i; index is its inverse.
+	nodes []T
+	index map[T]int
+
+	// parent[i] is the parent of node i, or i if it is a root.
+	parent []int
+}
+
+// number returns the number of x, assigning the next one if x is new.
+func (t *Tree[T]) number(x T) int {
+	i, ok := t.index[x]
+	if !ok {
+		i = len(t.nodes)
+		t.index[x] = i
+		t.nodes = append(t.nodes, x)
+		t.parent = append(t.parent, i)
+	}
+	return i
+}
+
+// FromParents returns the forest given by parent pointers: parent[x] is the
+// parent of x. A node is a root if it maps to itself or does not occur as
+// a key. Nodes on a parent cycle are not part of any tree; queries that
+// involve them have no answer.
+func FromParents[T comparable](parent map[T]T) *Tree[T] {
+	t := &Tree[T]{index: make(map[T]int, len(parent))}
+	for x, p := range parent {
+		i, j := t.number(x), t.number(p)
+		t.parent[i] = j
+	}
+	return t
+}
+
+// FromAdjacency returns the tree rooted at root whose edges are given as
+// adjacency lists. Each edge may be listed in one or both directions, so
+// both child lists and undirected neighbor lists are accepted; only nodes
+// reachable from root are part of the tree. If the adjacency lists contain
+// a cycle, the tree is the depth-first spanning tree.
+func FromAdjacency[T comparable](root T, adj map[T][]T) *Tree[T] {
+	t := &Tree[T]{index: make(map[T]int, len(adj))}
+	visited := []bool{true}
+	stack := []T{root}
+	t.number(root)
+	for len(stack) > 0 {
+		x := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		i := t.index[x]
+		for _, y := range adj[x] {
+			j := t.number(y)
+			if j == len(visited) {
+				visited = append(visited, false)
+			}
+			if !visited[j] {
+				visited[j] = true
+				t.parent[j] = i
+				stack = append(stack, y)
+			}
+		}
+	}
+	return t
+}
+
+// Len returns the number of nodes.
+func (t *Tree[T]) Len() int {
+	return len(t.nodes)
+}
+
+// LCA returns the lowest common ancestor of every query pair. ok[i] is
+// false if the nodes of the i-th query are in different trees, are not in
+// the forest, or lie on a parent cycle; lcas[i] is then the zero value.
+func (t *Tree[T]) LCA(queries [][2]T) (lcas []T, ok []bool) {
+	n := len(t.nodes)
+	lcas = make([]T, len(queries))
+	ok = make([]bool, len(queries))
+
+	// children and query incidences of every node, in CSR form
+	childStart := make([]int, n+1)
+	for i, p := range t.parent {
+		if p != i {
+			childStart[p+1]++
+		}
+	}
+	queryStart := make([]int, n+1)
+	qa := make([]int, len(queries))
+	qb := make([]int, len(queries))
+	for k, q := range queries {
+		a, okA := t.index[q[0]]
+		b, okB := t.index[q[1]]
+		if !okA || !okB {
+			qa[k], qb[k] = -1, -1
+			continue
+		}
+		qa[k], qb[k] = a, b
+		queryStart[a+1]++
+		queryStart[b+1]++
+	}
+	for i := 0; i < n; i++ {
+		childStart[i+1] += childStart[i]
+		queryStart[i+1] += queryStart[i]
+	}
+	children := make([]int, childStart[n])
+	queryIDs := make([]int, queryStart[n])
+	nextChild := append([]int(nil), childStart[:n]...)
+	nextQuery := append([]int(nil), queryStart[:n]...)
+	for i, p := range t.parent {
+		if p != i {
+			children[nextChild[p]] = i
+			nextChild[p]++
+		}
+	}
+	for k := range queries {
+		if qa[k] >= 0 {
+			queryIDs[nextQuery[qa[k]]] = k
+			nextQuery[qa[k]]++
+			queryIDs[nextQuery[qb[k]]] = k
+			nextQuery[qb[k]]++
+		}
+	}
+
+	// ancestor[r] is the deepest node on the current path whose subtree
+	// contains the DSU set rooted at r; tree[i] identifies i's tree once
+	// i has been visited
+	dsu := compact.New(n)
+	ancestor := make([]int, n)
+	tree := make([]int, n)
+	done := make([]bool, n)
+	for i := range tree {
+		tree[i] = -1
+	}
+
+	// each stack entry is a node and the position of its next child
+	type frame struct{ node, next int }
+	var stack []frame
+	for root, p := range t.parent {
+		if p != root {
+			continue
+		}
+		tree[root] = root
+		ancestor[root] = root
+		stack = append(stack, frame{root, childStart[root]})
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			v := top.node
+			if top.next < childStart[v+1] {
+				c := children[top.next]
+				top.next++
+				tree[c] = root
+				ancestor[c] = c
+				stack = append(stack, frame{c, childStart[c]})
+				continue
+			}
+
+			// v is finished: answer its queries whose other node is done
+			done[v] = true
+			for _, k := range queryIDs[queryStart[v]:queryStart[v+1]] {
+				other := qa[k] + qb[k] - v
+				if done[other] && tree[other] == root {
+					lcas[k], ok[k] = t.nodes[ancestor[dsu.Find(other)]], true
+				}
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				p := stack[len(stack)-1].node
+				dsu.Union(p, v)
+				ancestor[dsu.Find(p)] = p
+			}
+		}
+	}
+	return lcas, ok
+}
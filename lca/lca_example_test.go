@@ -0,0 +1,20 @@
+package lca
+
+import "fmt"
+
+// Example answers a batch of queries over a small taxonomy.
+func Example() {
+	parent := map[string]string{
+		"cat":    "mammal",
+		"dog":    "mammal",
+		"mammal": "animal",
+		"eagle":  "bird",
+		"bird":   "animal",
+	}
+
+	lcas, ok := FromParents(parent).LCA([][2]string{{"cat", "dog"}, {"dog", "eagle"}, {"cat", "mammal"}})
+	fmt.Println(lcas, ok)
+
+	// Output:
+	// [mammal animal mammal] [true true true]
+}
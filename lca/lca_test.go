@@ -0,0 +1,110 @@
+package lca
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// naiveLCA walks parent pointers to find the lowest common ancestor.
+func naiveLCA(parent map[int]int, a, b int) (int, bool) {
+	onPath := map[int]bool{}
+	for x := a; ; x = parent[x] {
+		onPath[x] = true
+		if p, ok := parent[x]; !ok || p == x {
+			break
+		}
+	}
+	for x := b; ; x = parent[x] {
+		if onPath[x] {
+			return x, true
+		}
+		if p, ok := parent[x]; !ok || p == x {
+			return 0, false
+		}
+	}
+}
+
+// TestLCAMatchesNaive cross-checks random forests against parent walks.
+func TestLCAMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 500
+	parent := make(map[int]int, n)
+	for x := 0; x < n; x++ {
+		if x%100 == 0 {
+			parent[x] = x // a new tree every 100 nodes
+		} else {
+			parent[x] = x - 1 - rng.Intn(x%100)
+		}
+	}
+	queries := make([][2]int, 2000)
+	for i := range queries {
+		queries[i] = [2]int{rng.Intn(n), rng.Intn(n)}
+	}
+
+	lcas, ok := FromParents(parent).LCA(queries)
+	for i, q := range queries {
+		want, wantOK := naiveLCA(parent, q[0], q[1])
+		if ok[i] != wantOK || (wantOK && lcas[i] != want) {
+			t.Fatalf("LCA(%d, %d) = %d, %v, want %d, %v", q[0], q[1], lcas[i], ok[i], want, wantOK)
+		}
+	}
+}
+
+// TestLCAFromAdjacency checks undirected and child-list adjacency.
+func TestLCAFromAdjacency(t *testing.T) {
+	//        ceo
+	//       /   \
+	//     cto    cfo
+	//    /   \      \
+	//  dev1  dev2   acct
+	children := map[string][]string{
+		"ceo": {"cto", "cfo"},
+		"cto": {"dev1", "dev2"},
+		"cfo": {"acct"},
+	}
+	undirected := map[string][]string{
+		"ceo":  {"cto", "cfo"},
+		"cto":  {"ceo", "dev1", "dev2"},
+		"cfo":  {"ceo", "acct"},
+		"dev1": {"cto"},
+		"dev2": {"cto"},
+		"acct": {"cfo"},
+	}
+	queries := [][2]string{{"dev1", "dev2"}, {"dev1", "acct"}, {"cto", "dev2"}, {"acct", "acct"}, {"dev1", "intern"}}
+	want := []string{"cto", "ceo", "cto", "acct", ""}
+	wantOK := []bool{true, true, true, true, false}
+
+	for _, tree := range []*Tree[string]{FromAdjacency("ceo", children), FromAdjacency("ceo", undirected)} {
+		if tree.Len() != 6 {
+			t.Fatalf("expected 6 nodes, got %d", tree.Len())
+		}
+		lcas, ok := tree.LCA(queries)
+		for i := range queries {
+			if lcas[i] != want[i] || ok[i] != wantOK[i] {
+				t.Fatalf("LCA%v = %q, %v, want %q, %v", queries[i], lcas[i], ok[i], want[i], wantOK[i])
+			}
+		}
+	}
+}
+
+// TestLCADeepPath ensures very deep trees do not overflow the stack.
+func TestLCADeepPath(t *testing.T) {
+	const n = 300_000
+	parent := make(map[int]int, n)
+	for x := 1; x < n; x++ {
+		parent[x] = x - 1
+	}
+	lcas, ok := FromParents(parent).LCA([][2]int{{n - 1, n / 2}, {3, n - 2}})
+	if !ok[0] || !ok[1] || lcas[0] != n/2 || lcas[1] != 3 {
+		t.Fatalf("unexpected answers %v %v", lcas, ok)
+	}
+}
+
+// TestLCAParentCycle ensures nodes on a parent cycle have no answer.
+func TestLCAParentCycle(t *testing.T) {
+	parent := map[string]string{"a": "b", "b": "a", "c": "root", "d": "root"}
+	lcas, ok := FromParents(parent).LCA([][2]string{{"a", "b"}, {"c", "d"}, {"a", "c"}})
+	if ok[0] || !ok[1] || ok[2] || lcas[1] != "root" {
+		t.Fatalf("unexpected answers %v %v", lcas, ok)
+	}
+}
@@ -0,0 +1,217 @@
+// Package metrics compares two partitions of the same elements, such as a
+// clustering and its ground truth, with the usual external clustering
+// metrics: the Rand and adjusted Rand index, variation of information,
+// pairwise precision, recall and F1, B-cubed precision, recall and F1, and
+// purity.
+//
+// All metrics are computed from the contingency table of the partitions,
+// the sizes of the nonempty intersections of a class of one with a class of
+// the other, which takes time linear in the number of elements; pair
+// counts follow from the table without enumerating pairs. The partitions
+// are given as gdsu.DSU values or as their Groups output. An element that
+// occurs in only one of them counts as a singleton class in the other.
+package metrics
+
+import (
+	"math"
+
+	"github.com/arunksaha/gdsu"
+)
+
+// Table is the contingency table of a clustering a, the one being
+// evaluated, against a reference clustering b.
+type Table struct {
+	// cells holds the sizes of the nonempty intersections, and rows and
+	// cols the sizes of the classes of a and b.
+	cells      []int
+	rows, cols []int
+	n          int
+
+	// cellRow[k] and cellCol[k] are the classes of cells[k].
+	cellRow, cellCol []int
+}
+
+// New returns the contingency table of the DSUs a and b.
+func New[T comparable](a, b gdsu.DSU[T]) *Table {
+	return FromGroups(a.Groups(), b.Groups())
+}
+
+// FromGroups returns the contingency table of the partitions a and b, given
+// in the shape returned by gdsu.DSU.Groups.
+func FromGroups[T comparable](a, b map[T][]T) *Table {
+	t := &Table{}
+
+	// classOf maps every element of b to the index of its class
+	classOf := make(map[T]int)
+	for _, members := range b {
+		for _, x := range members {
+			classOf[x] = len(t.cols)
+		}
+		t.cols = append(t.cols, len(members))
+	}
+
+	seen := make(map[T]bool, len(classOf))
+	cell := make(map[[2]int]int)
+	add := func(row, col int) {
+		key := [2]int{row, col}
+		k, ok := cell[key]
+		if !ok {
+			k = len(t.cells)
+			cell[key] = k
+			t.cells = append(t.cells, 0)
+			t.cellRow = append(t.cellRow, row)
+			t.cellCol = append(t.cellCol, col)
+		}
+		t.cells[k]++
+		t.n++
+	}
+	for _, members := range a {
+		row := len(t.rows)
+		t.rows = append(t.rows, len(members))
+		for _, x := range members {
+			seen[x] = true
+			col, ok := classOf[x]
+			if !ok {
+				col = len(t.cols)
+				t.cols = append(t.cols, 1)
+			}
+			add(row, col)
+		}
+	}
+
+	// elements only in b are singletons of a
+	for x, col := range classOf {
+		if !seen[x] {
+			t.rows = append(t.rows, 1)
+			add(len(t.rows)-1, col)
+		}
+	}
+	return t
+}
+
+// Len returns the number of elements.
+func (t *Table) Len() int {
+	return t.n
+}
+
+// pairs returns the number of unordered pairs of n elements.
+func pairs(n int) float64 {
+	return float64(n) * float64(n-1) / 2
+}
+
+// pairCounts returns the number of pairs together in a cell, in a row, in
+// a column, and in total.
+func (t *Table) pairCounts() (cells, rows, cols, total float64) {
+	for _, c := range t.cells {
+		cells += pairs(c)
+	}
+	for _, r := range t.rows {
+		rows += pairs(r)
+	}
+	for _, c := range t.cols {
+		cols += pairs(c)
+	}
+	return cells, rows, cols, pairs(t.n)
+}
+
+// Rand returns the Rand index: the fraction of pairs of elements on which a
+// and b agree, being together in both or apart in both. It is 1 if there
+// are fewer than two elements.
+func (t *Table) Rand() float64 {
+	cells, rows, cols, total := t.pairCounts()
+	if total == 0 {
+		return 1
+	}
+	return (total + 2*cells - rows - cols) / total
+}
+
+// AdjustedRand returns the adjusted Rand index of Hubert and Arabie
+// (1985): the Rand index corrected for chance, which is 1 for identical
+// partitions and 0 in expectation for random ones with the same class
+// sizes. It is 1 if the correction is undefined because both partitions
+// are trivial in the same way.
+func (t *Table) AdjustedRand() float64 {
+	cells, rows, cols, total := t.pairCounts()
+	if total == 0 {
+		return 1
+	}
+	expected := rows * cols / total
+	maximum := (rows + cols) / 2
+	if maximum == expected {
+		return 1
+	}
+	return (cells - expected) / (maximum - expected)
+}
+
+// VariationOfInformation returns the variation of information of Meilă
+// (2007), H(a|b) + H(b|a) in nats: 0 for identical partitions, and at most
+// log n.
+func (t *Table) VariationOfInformation() float64 {
+	n := float64(t.n)
+	vi := 0.0
+	for k, c := range t.cells {
+		nc := float64(c)
+		nr, nl := float64(t.rows[t.cellRow[k]]), float64(t.cols[t.cellCol[k]])
+		vi -= nc / n * (math.Log(nc/nr) + math.Log(nc/nl))
+	}
+	return vi
+}
+
+// f1 returns the harmonic mean of precision and recall.
+func f1(precision, recall float64) float64 {
+	if precision+recall == 0 {
+		return 0
+	}
+	return 2 * precision * recall / (precision + recall)
+}
+
+// Pairwise returns the pairwise precision, the fraction of pairs together
+// in a that are together in b, the pairwise recall, the fraction of pairs
+// together in b that are together in a, and their F1 score. Precision is 1
+// if a has no pairs together, and recall if b has none.
+func (t *Table) Pairwise() (precision, recall, f float64) {
+	cells, rows, cols, _ := t.pairCounts()
+	precision, recall = 1, 1
+	if rows > 0 {
+		precision = cells / rows
+	}
+	if cols > 0 {
+		recall = cells / cols
+	}
+	return precision, recall, f1(precision, recall)
+}
+
+// BCubed returns the B-cubed precision and recall of Bagga and Baldwin
+// (1998), the averages over all elements x of the fraction of the class of
+// x in a that is in the class of x in b, and vice versa, and their F1
+// score. All three are 1 if there are no elements.
+func (t *Table) BCubed() (precision, recall, f float64) {
+	if t.n == 0 {
+		return 1, 1, 1
+	}
+	for k, c := range t.cells {
+		nc := float64(c)
+		precision += nc * nc / float64(t.rows[t.cellRow[k]])
+		recall += nc * nc / float64(t.cols[t.cellCol[k]])
+	}
+	n := float64(t.n)
+	precision, recall = precision/n, recall/n
+	return precision, recall, f1(precision, recall)
+}
+
+// Purity returns the fraction of elements that belong to the most common
+// class of b within their class of a. It is 1 if there are no elements.
+func (t *Table) Purity() float64 {
+	if t.n == 0 {
+		return 1
+	}
+	best := make([]int, len(t.rows))
+	for k, c := range t.cells {
+		best[t.cellRow[k]] = max(best[t.cellRow[k]], c)
+	}
+	total := 0
+	for _, b := range best {
+		total += b
+	}
+	return float64(total) / float64(t.n)
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// Example evaluates an entity-resolution run against the ground truth.
+func Example() {
+	truth := sparse.New[string]()
+	truth.UnionAll("Bob Smith", "Robert Smith", "R. Smith")
+	truth.UnionAll("Ann Lee", "A. Lee")
+
+	run := sparse.New[string]()
+	run.UnionAll("Bob Smith", "Robert Smith")
+	run.UnionAll("R. Smith", "Ann Lee", "A. Lee")
+
+	t := New[string](run, truth)
+	p, r, f := t.Pairwise()
+	fmt.Printf("pairwise P=%.2f R=%.2f F1=%.2f\n", p, r, f)
+	p, r, f = t.BCubed()
+	fmt.Printf("B-cubed  P=%.2f R=%.2f F1=%.2f\n", p, r, f)
+	fmt.Printf("Rand=%.2f ARI=%.2f purity=%.2f\n", t.Rand(), t.AdjustedRand(), t.Purity())
+
+	// Output:
+	// pairwise P=0.50 R=0.50 F1=0.50
+	// B-cubed  P=0.73 R=0.73 F1=0.73
+	// Rand=0.60 ARI=0.17 purity=0.80
+}
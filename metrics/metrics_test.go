@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/arunksaha/gdsu/compact"
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// fromLabels returns the groups of [0, len(labels)) by label.
+func fromLabels(labels []int) map[int][]int {
+	first := make(map[int]int)
+	groups := make(map[int][]int)
+	for x, l := range labels {
+		if _, ok := first[l]; !ok {
+			first[l] = x
+		}
+		groups[first[l]] = append(groups[first[l]], x)
+	}
+	return groups
+}
+
+// near reports whether a and b are equal up to rounding.
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// TestPairMetricsMatchBruteForce compares the pair-counting metrics with
+// counts over all pairs of random labelings.
+func TestPairMetricsMatchBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		n := 2 + rng.Intn(40)
+		la, lb := make([]int, n), make([]int, n)
+		ka, kb := 1+rng.Intn(n), 1+rng.Intn(n)
+		for x := range la {
+			la[x], lb[x] = rng.Intn(ka), rng.Intn(kb)
+		}
+		tab := FromGroups(fromLabels(la), fromLabels(lb))
+
+		var both, onlyA, onlyB, neither float64
+		var bcP, bcR float64
+		for x := 0; x < n; x++ {
+			var sameA, sameB, sameBoth float64
+			for y := 0; y < n; y++ {
+				inA, inB := la[x] == la[y], lb[x] == lb[y]
+				if inA {
+					sameA++
+				}
+				if inB {
+					sameB++
+				}
+				if inA && inB {
+					sameBoth++
+				}
+				if y <= x {
+					continue
+				}
+				switch {
+				case inA && inB:
+					both++
+				case inA:
+					onlyA++
+				case inB:
+					onlyB++
+				default:
+					neither++
+				}
+			}
+			bcP += sameBoth / sameA
+			bcR += sameBoth / sameB
+		}
+		total := both + onlyA + onlyB + neither
+		if got := tab.Rand(); !near(got, (both+neither)/total) {
+			t.Fatalf("trial %d: Rand = %v", trial, got)
+		}
+		p, r, _ := tab.Pairwise()
+		if both+onlyA > 0 && !near(p, both/(both+onlyA)) || both+onlyB > 0 && !near(r, both/(both+onlyB)) {
+			t.Fatalf("trial %d: Pairwise = %v, %v", trial, p, r)
+		}
+		p, r, _ = tab.BCubed()
+		if !near(p, bcP/float64(n)) || !near(r, bcR/float64(n)) {
+			t.Fatalf("trial %d: BCubed = %v, %v", trial, p, r)
+		}
+		if tab.Len() != n {
+			t.Fatalf("trial %d: Len = %d", trial, tab.Len())
+		}
+	}
+}
+
+// TestKnownValues checks the metrics of a small example against values
+// computed by hand.
+func TestKnownValues(t *testing.T) {
+	truth := fromLabels([]int{0, 0, 0, 1, 1, 1})
+	pred := fromLabels([]int{0, 0, 1, 1, 2, 2})
+	tab := FromGroups(pred, truth)
+
+	if got := tab.Rand(); !near(got, 10.0/15) {
+		t.Fatalf("Rand = %v", got)
+	}
+	// 2 pairs together in both; expected = 3 * 6 / 15; maximum = 9 / 2
+	if got, want := tab.AdjustedRand(), (2-1.2)/(4.5-1.2); !near(got, want) {
+		t.Fatalf("AdjustedRand = %v, want %v", got, want)
+	}
+	if p, r, f := tab.Pairwise(); !near(p, 2.0/3) || !near(r, 1.0/3) || !near(f, 4.0/9) {
+		t.Fatalf("Pairwise = %v, %v, %v", p, r, f)
+	}
+	if got := tab.Purity(); !near(got, 5.0/6) {
+		t.Fatalf("Purity = %v", got)
+	}
+	// H(truth|pred) = 2/6 log 2, H(pred|truth) = 2 * (3/6) * H(2/3, 1/3)
+	h := -(2.0/3*math.Log(2.0/3) + 1.0/3*math.Log(1.0/3))
+	if got, want := tab.VariationOfInformation(), 2.0/6*math.Log(2)+h; !near(got, want) {
+		t.Fatalf("VariationOfInformation = %v, want %v", got, want)
+	}
+}
+
+func TestIdenticalAndTrivial(t *testing.T) {
+	p := fromLabels([]int{0, 1, 1, 2, 2, 2})
+	tab := FromGroups(p, p)
+	if tab.Rand() != 1 || tab.AdjustedRand() != 1 || tab.VariationOfInformation() != 0 || tab.Purity() != 1 {
+		t.Fatal("identical partitions are not perfect")
+	}
+	if _, _, f := tab.BCubed(); f != 1 {
+		t.Fatalf("BCubed F1 = %v", f)
+	}
+
+	singletons := fromLabels([]int{0, 1, 2, 3})
+	one := fromLabels([]int{0, 0, 0, 0})
+	if got := FromGroups(singletons, singletons).AdjustedRand(); got != 1 {
+		t.Fatalf("AdjustedRand of singletons = %v", got)
+	}
+	if got := FromGroups(singletons, one).AdjustedRand(); got != 0 {
+		t.Fatalf("AdjustedRand of singletons against one class = %v", got)
+	}
+	if p, r, _ := FromGroups(singletons, one).Pairwise(); p != 1 || r != 0 {
+		t.Fatalf("Pairwise = %v, %v", p, r)
+	}
+
+	empty := FromGroups(map[int][]int{}, map[int][]int{})
+	if empty.Rand() != 1 || empty.AdjustedRand() != 1 || empty.Purity() != 1 || empty.VariationOfInformation() != 0 {
+		t.Fatal("empty partitions are not perfect")
+	}
+}
+
+func TestNewFromDSUs(t *testing.T) {
+	a, b := compact.New(5), sparse.New[int]()
+	a.Union(0, 1)
+	a.Union(2, 3)
+	b.UnionAll(0, 1, 2, 3)
+	// 4 is missing from b and counts as a singleton there
+	tab := New[int](a, b)
+	if tab.Len() != 5 {
+		t.Fatalf("Len = %d", tab.Len())
+	}
+	if got := tab.Purity(); got != 1 {
+		t.Fatalf("Purity = %v", got)
+	}
+	if p, r, _ := tab.Pairwise(); p != 1 || !near(r, 2.0/6) {
+		t.Fatalf("Pairwise = %v, %v", p, r)
+	}
+
+	// and an element missing from a counts as a singleton there
+	b.Find(7)
+	if got := New[int](a, b).Len(); got != 6 {
+		t.Fatalf("Len = %d", got)
+	}
+}
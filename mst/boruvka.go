@@ -0,0 +1,167 @@
+package mst
+
+import (
+	"cmp"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/arunksaha/gdsu/compact"
+)
+
+// Boruvka returns a minimum spanning forest of the graph over the vertices
+// [0, n) with the given edges, computed with Borůvka's algorithm using up
+// to workers goroutines.
+//
+// Every round, each component concurrently finds its cheapest outgoing
+// edge, and then the components are merged along those edges. Components
+// are tracked with a compact.DSU. Every remaining edge carries the roots
+// of its endpoints from the previous round, and after the merges every old
+// root is pointed directly at its new root, so that the parallel searches
+// only read the parent slice; the bookkeeping of a round touches only the
+// roots that were offered an edge, not all n vertices. Ties between
+// equal weights are broken by the position of the edge in the input, which
+// makes the forest unique: its Edges are exactly those Kruskal returns for
+// the same input, in the same order. Unlike Kruskal, Components includes
+// every vertex in [0, n), keyed by the minimum vertex of each component.
+//
+// If workers <= 0, runtime.GOMAXPROCS(0) is used.
+// Panics if any edge has a vertex out of range.
+func Boruvka[W cmp.Ordered](n int, edges []Edge[int, W], workers int) *Forest[int, W] {
+	if n < 0 {
+		n = 0
+	}
+	for _, e := range edges {
+		if e.U < 0 || e.U >= n || e.V < 0 || e.V >= n {
+			panic("mst: vertex out of range in Boruvka")
+		}
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// less orders edges by weight, then by position in the input
+	less := func(i, j int) bool {
+		if c := cmp.Compare(edges[i].Weight, edges[j].Weight); c != 0 {
+			return c < 0
+		}
+		return i < j
+	}
+
+	// live holds the edges whose endpoints were in different components at
+	// the start of the round, with the roots of the endpoints then
+	type liveEdge struct{ i, u, v int }
+	live := make([]liveEdge, len(edges))
+	for i, e := range edges {
+		live[i] = liveEdge{i, e.U, e.V}
+	}
+
+	// best[r] is one plus the cheapest outgoing edge of the component
+	// rooted at r found so far this round, or 0 if none
+	dsu := compact.New(n)
+	best := make([]atomic.Int64, n)
+	var chosen []int
+
+	for len(live) > 0 {
+		// find the cheapest outgoing edge of every component, dropping
+		// edges that have become internal; every chunk records the roots
+		// it made the first offer to
+		chunks := split(live, workers)
+		survivors := make([][]liveEdge, len(chunks))
+		offered := make([][]int, len(chunks))
+		parallel(len(chunks), func(c int) {
+			kept := chunks[c][:0]
+			for _, e := range chunks[c] {
+				ru, rv := dsu.Find(e.u), dsu.Find(e.v)
+				if ru == rv {
+					continue
+				}
+				kept = append(kept, liveEdge{e.i, ru, rv})
+				for _, r := range []int{ru, rv} {
+					if offer(best, r, e.i, less) {
+						offered[c] = append(offered[c], r)
+					}
+				}
+			}
+			survivors[c] = kept
+		})
+		live = slices.Concat(survivors...)
+		roots := slices.Concat(offered...)
+
+		// merge along the chosen edges; an edge chosen by both of its
+		// components is only added once
+		for _, r := range roots {
+			if i := int(best[r].Load()) - 1; dsu.Union(edges[i].U, edges[i].V) {
+				chosen = append(chosen, i)
+			}
+		}
+
+		// point every old root directly at its new root, so that the
+		// Finds of the next round, which start from old roots, only read
+		// the parent slice
+		for _, r := range roots {
+			dsu.Find(r)
+		}
+		parallel(len(offered), func(c int) {
+			for _, r := range offered[c] {
+				best[r].Store(0)
+			}
+		})
+	}
+	dsu.Canonicalize()
+
+	slices.Sort(chosen)
+	slices.SortStableFunc(chosen, func(i, j int) int { return cmp.Compare(edges[i].Weight, edges[j].Weight) })
+
+	forest := &Forest[int, W]{
+		Components: dsu.Groups(),
+		Trees:      make(map[int][]Edge[int, W]),
+	}
+	for _, i := range chosen {
+		e := edges[i]
+		forest.Edges = append(forest.Edges, e)
+		forest.Weight += e.Weight
+		root := dsu.Find(e.U)
+		forest.Trees[root] = append(forest.Trees[root], e)
+	}
+	return forest
+}
+
+// offer lowers best[r] to edge i if i is cheaper than the current choice,
+// and reports whether r had no choice before.
+func offer(best []atomic.Int64, r, i int, less func(i, j int) bool) bool {
+	for {
+		cur := best[r].Load()
+		if cur > 0 && !less(i, int(cur)-1) {
+			return false
+		}
+		if best[r].CompareAndSwap(cur, int64(i)+1) {
+			return cur == 0
+		}
+	}
+}
+
+// split divides xs into at most k contiguous chunks of nearly equal size.
+func split[E any](xs []E, k int) [][]E {
+	k = max(1, min(k, len(xs)))
+	chunks := make([][]E, 0, k)
+	size := (len(xs) + k - 1) / k
+	for lo := 0; lo < len(xs); lo += size {
+		chunks = append(chunks, xs[lo:min(lo+size, len(xs))])
+	}
+	return chunks
+}
+
+// parallel calls fn(c) for every c in [0, k) concurrently.
+func parallel(k int, fn func(c int)) {
+	var wg sync.WaitGroup
+	for c := 0; c < k; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			fn(c)
+		}(c)
+	}
+	wg.Wait()
+}
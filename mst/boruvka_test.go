@@ -0,0 +1,62 @@
+package mst
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// randomGraph returns m random edges over [0, n) with weights in [0, w).
+func randomGraph(n, m, w int, seed int64) []Edge[int, int] {
+	rng := rand.New(rand.NewSource(seed))
+	edges := make([]Edge[int, int], m)
+	for i := range edges {
+		edges[i] = Edge[int, int]{rng.Intn(n), rng.Intn(n), rng.Intn(w)}
+	}
+	return edges
+}
+
+// TestBoruvkaMatchesKruskal ensures Borůvka returns exactly Kruskal's
+// edges, including on graphs with many equal weights.
+func TestBoruvkaMatchesKruskal(t *testing.T) {
+	const n = 2000
+	for _, tc := range []struct{ m, w int }{{n / 2, 1000}, {2 * n, 1000}, {5 * n, 3}, {5 * n, 1}} {
+		edges := randomGraph(n, tc.m, tc.w, int64(tc.m*tc.w))
+		want := KruskalVertices([]int{0, n - 1}, edges)
+		for _, workers := range []int{1, 3, 0} {
+			got := Boruvka(n, edges, workers)
+			if got.Weight != want.Weight || !slices.Equal(got.Edges, want.Edges) {
+				t.Fatalf("m=%d w=%d workers=%d: forest differs from Kruskal", tc.m, tc.w, workers)
+			}
+		}
+	}
+}
+
+// TestBoruvkaComponents checks components, trees and isolated vertices.
+func TestBoruvkaComponents(t *testing.T) {
+	edges := []Edge[int, float64]{{4, 3, 1.5}, {3, 1, 0.5}, {1, 4, 0.25}, {6, 5, 2}}
+	f := Boruvka(7, edges, 2)
+
+	if f.Weight != 2.75 || len(f.Edges) != 3 {
+		t.Fatalf("expected 3 edges of weight 2.75, got %d of weight %v", len(f.Edges), f.Weight)
+	}
+	// components are keyed by their minimum vertex
+	for root, want := range map[int][]int{0: {0}, 1: {1, 3, 4}, 2: {2}, 5: {5, 6}} {
+		if got := f.Components[root]; !slices.Equal(got, want) {
+			t.Fatalf("component %d = %v, want %v", root, got, want)
+		}
+	}
+	if len(f.Trees[1]) != 2 || len(f.Trees[5]) != 1 || len(f.Trees) != 2 {
+		t.Fatalf("unexpected trees %v", f.Trees)
+	}
+}
+
+// TestBoruvkaOutOfRange ensures Boruvka panics on vertices outside [0, n).
+func TestBoruvkaOutOfRange(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected panic on out-of-range vertex, got none")
+		}
+	}()
+	Boruvka(2, []Edge[int, int]{{0, 2, 1}}, 1)
+}
@@ -0,0 +1,186 @@
+// Package mst computes minimum (or maximum) spanning forests of weighted
+// undirected graphs with Kruskal's algorithm.
+//
+// Vertices may be of any comparable type and weights of any ordered type.
+// When the vertices are ints in a dense range [0, n), the forest is built
+// on a compact.DSU; otherwise it is built on a sparse.DSU.
+package mst
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/arunksaha/gdsu"
+	"github.com/arunksaha/gdsu/compact"
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// Edge is a weighted undirected edge between U and V.
+type Edge[T comparable, W cmp.Ordered] struct {
+	U, V   T
+	Weight W
+}
+
+// Forest is a spanning forest computed by Kruskal.
+type Forest[T comparable, W cmp.Ordered] struct {
+	// Edges holds the forest edges in the order they were added.
+	Edges []Edge[T, W]
+
+	// Weight is the total weight of Edges.
+	Weight W
+
+	// Components maps the representative of every component to its
+	// vertices, in the shape returned by gdsu.DSU.Groups.
+	Components map[T][]T
+
+	// Trees maps the representative of every component to its forest
+	// edges; components consisting of a single vertex have no entry.
+	Trees map[T][]Edge[T, W]
+}
+
+// Option configures Kruskal.
+type Option func(*options)
+
+type options struct {
+	maximum  bool
+	clusters int
+}
+
+// Maximum makes Kruskal compute a maximum spanning forest instead of a
+// minimum one.
+func Maximum() Option {
+	return func(o *options) { o.maximum = true }
+}
+
+// Clusters makes Kruskal stop as soon as the forest has k components,
+// which yields a maximum-spacing k-clustering of the vertices.
+func Clusters(k int) Option {
+	return func(o *options) { o.clusters = k }
+}
+
+// denseFactor bounds how sparse int vertices may be while still being
+// stored in a compact.DSU: the range [0, n) may be at most denseFactor
+// times larger than the number of edges and extra vertices.
+const denseFactor = 4
+
+// Kruskal returns a minimum spanning forest of the graph with the given
+// edges. Edges of equal weight are considered in input order, so the result
+// is deterministic.
+func Kruskal[T comparable, W cmp.Ordered](edges []Edge[T, W], opts ...Option) *Forest[T, W] {
+	return KruskalVertices(nil, edges, opts...)
+}
+
+// KruskalVertices is like Kruskal, but the graph has the vertices in extra
+// in addition to the edge endpoints, such as isolated vertices that should
+// count as clusters of their own.
+func KruskalVertices[T comparable, W cmp.Ordered](extra []T, edges []Edge[T, W], opts ...Option) *Forest[T, W] {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	sorted := slices.Clone(edges)
+	if o.maximum {
+		slices.SortStableFunc(sorted, func(a, b Edge[T, W]) int { return cmp.Compare(b.Weight, a.Weight) })
+	} else {
+		slices.SortStableFunc(sorted, func(a, b Edge[T, W]) int { return cmp.Compare(a.Weight, b.Weight) })
+	}
+
+	dsu, components := newDSU(edges, extra)
+	forest := &Forest[T, W]{}
+	for _, e := range sorted {
+		if components <= o.clusters {
+			break
+		}
+		if dsu.Union(e.U, e.V) {
+			forest.Edges = append(forest.Edges, e)
+			forest.Weight += e.Weight
+			components--
+		}
+	}
+
+	forest.Components = dsu.Groups()
+	forest.Trees = make(map[T][]Edge[T, W])
+	for _, e := range forest.Edges {
+		root := dsu.Find(e.U)
+		forest.Trees[root] = append(forest.Trees[root], e)
+	}
+	return forest
+}
+
+// newDSU returns a DSU containing every edge endpoint and every extra
+// vertex as a singleton set, and the number of such vertices. It is backed
+// by compact.DSU when the vertices are ints in a dense range [0, n) and by
+// sparse.DSU otherwise.
+func newDSU[T comparable, W cmp.Ordered](edges []Edge[T, W], extra []T) (gdsu.DSU[T], int) {
+	if intEdges, ok := any(edges).([]Edge[int, W]); ok {
+		if dsu, count, ok := newDense(intEdges, any(extra).([]int)); ok {
+			return any(dsu).(gdsu.DSU[T]), count
+		}
+	}
+
+	dsu := sparse.New(extra...)
+	for _, e := range edges {
+		dsu.Find(e.U)
+		dsu.Find(e.V)
+	}
+	return dsu, len(dsu.Groups())
+}
+
+// denseDSU is a compact.DSU over [0, n) whose Groups reports only the
+// vertices present in the graph.
+type denseDSU struct {
+	*compact.DSU
+	present []bool
+}
+
+// Groups returns a map from root -> slice of present vertices in that set.
+func (d denseDSU) Groups() map[int][]int {
+	groups := make(map[int][]int)
+	for x, ok := range d.present {
+		if ok {
+			root := d.Find(x)
+			groups[root] = append(groups[root], x)
+		}
+	}
+	return groups
+}
+
+// newDense returns a denseDSU over the vertices that occur and their
+// number, or false if the vertices are negative or too sparse for compact
+// storage.
+func newDense[W cmp.Ordered](edges []Edge[int, W], extra []int) (gdsu.DSU[int], int, bool) {
+	n := 0
+	for _, e := range edges {
+		if e.U < 0 || e.V < 0 {
+			return nil, 0, false
+		}
+		n = max(n, e.U+1, e.V+1)
+	}
+	for _, v := range extra {
+		if v < 0 {
+			return nil, 0, false
+		}
+		n = max(n, v+1)
+	}
+	if n > denseFactor*(2*len(edges)+len(extra)) {
+		return nil, 0, false
+	}
+
+	present := make([]bool, n)
+	count := 0
+	mark := func(v int) {
+		if !present[v] {
+			present[v] = true
+			count++
+		}
+	}
+	for _, e := range edges {
+		mark(e.U)
+		mark(e.V)
+	}
+	for _, v := range extra {
+		mark(v)
+	}
+	return denseDSU{DSU: compact.New(n), present: present}, count, true
+}
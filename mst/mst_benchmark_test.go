@@ -0,0 +1,23 @@
+package mst
+
+import "testing"
+
+// NumVertices is the number of vertices used for benchmarking.
+const NumVertices = 100_000
+
+// BenchmarkSpanningForest compares Kruskal with Borůvka on a random graph.
+func BenchmarkSpanningForest(b *testing.B) {
+	edges := randomGraph(NumVertices, 8*NumVertices, 1_000_000, 1)
+
+	b.Run("Kruskal", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_ = Kruskal(edges)
+		}
+	})
+
+	b.Run("Boruvka", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_ = Boruvka(NumVertices, edges, 0)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package mst
+
+import "fmt"
+
+// ExampleKruskal computes a minimum spanning tree over string vertices.
+func ExampleKruskal() {
+	edges := []Edge[string, int]{
+		{"a", "b", 4}, {"b", "c", 1}, {"a", "c", 2}, {"c", "d", 7},
+	}
+
+	f := Kruskal(edges)
+	fmt.Println(f.Weight)
+	for _, e := range f.Edges {
+		fmt.Println(e.U, e.V, e.Weight)
+	}
+
+	// Output:
+	// 10
+	// b c 1
+	// a c 2
+	// c d 7
+}
+
+// ExampleClusters splits points into two clusters by stopping early.
+func ExampleClusters() {
+	edges := []Edge[int, float64]{
+		{0, 1, 0.5}, {1, 2, 0.7}, {2, 3, 9.0}, {3, 4, 0.2},
+	}
+
+	f := Kruskal(edges, Clusters(2))
+	fmt.Println(len(f.Components), f.Weight)
+
+	// Output:
+	// 2 1.4
+}
+
+// ExampleBoruvka computes the same forest as Kruskal with parallel rounds.
+func ExampleBoruvka() {
+	edges := []Edge[int, int]{{0, 1, 4}, {1, 2, 1}, {0, 2, 2}, {2, 3, 7}}
+
+	f := Boruvka(4, edges, 0)
+	fmt.Println(f.Weight, f.Edges)
+
+	// Output:
+	// 10 [{1 2 1} {0 2 2} {2 3 7}]
+}
@@ -0,0 +1,157 @@
+package mst
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// classic is the textbook 7-vertex example whose minimum spanning tree
+// weighs 39 and whose maximum spanning tree weighs 59.
+var classic = []Edge[string, int]{
+	{"A", "B", 7}, {"A", "D", 5}, {"B", "C", 8}, {"B", "D", 9},
+	{"B", "E", 7}, {"C", "E", 5}, {"D", "E", 15}, {"D", "F", 6},
+	{"E", "F", 8}, {"E", "G", 9}, {"F", "G", 11},
+}
+
+// TestKruskalMinimum checks the weight and size of a minimum spanning tree.
+func TestKruskalMinimum(t *testing.T) {
+	f := Kruskal(classic)
+	if f.Weight != 39 || len(f.Edges) != 6 {
+		t.Fatalf("expected 6 edges of weight 39, got %d edges of weight %d", len(f.Edges), f.Weight)
+	}
+	if len(f.Components) != 1 || len(f.Trees) != 1 {
+		t.Fatalf("expected a single tree, got %d components", len(f.Components))
+	}
+	for i := 1; i < len(f.Edges); i++ {
+		if f.Edges[i].Weight < f.Edges[i-1].Weight {
+			t.Fatal("edges should be added in non-decreasing weight order")
+		}
+	}
+}
+
+// TestKruskalMaximum checks the weight of a maximum spanning tree.
+func TestKruskalMaximum(t *testing.T) {
+	f := Kruskal(classic, Maximum())
+	if f.Weight != 59 || len(f.Edges) != 6 {
+		t.Fatalf("expected 6 edges of weight 59, got %d edges of weight %d", len(f.Edges), f.Weight)
+	}
+}
+
+// TestKruskalClusters checks early stopping at k components, counting
+// isolated vertices added through KruskalVertices.
+func TestKruskalClusters(t *testing.T) {
+	edges := []Edge[int, float64]{
+		{0, 1, 1}, {1, 2, 1.5}, {3, 4, 1}, {2, 3, 10}, {4, 5, 0.5},
+	}
+
+	f := Kruskal(edges, Clusters(2))
+	if len(f.Components) != 2 || f.Weight != 4 {
+		t.Fatalf("expected 2 clusters and weight 4, got %d and %v", len(f.Components), f.Weight)
+	}
+
+	f = KruskalVertices([]int{9}, edges, Clusters(2))
+	if len(f.Components) != 2 || !f.connected(0, 5) {
+		t.Fatalf("expected {0..5} and {9} as clusters, got %v", f.Components)
+	}
+	if _, ok := f.Trees[9]; ok {
+		t.Fatal("isolated vertex should have no tree")
+	}
+}
+
+// TestKruskalVerticesTyped checks extra vertices of a non-int vertex type.
+func TestKruskalVerticesTyped(t *testing.T) {
+	f := KruskalVertices([]int64{5}, []Edge[int64, int]{{1, 2, 3}})
+	if len(f.Components) != 2 || !f.connected(1, 2) || f.Weight != 3 {
+		t.Fatalf("expected {1, 2} and {5} as components, got %v", f.Components)
+	}
+}
+
+// connected reports whether x and y are in the same component of f.
+func (f *Forest[T, W]) connected(x, y T) bool {
+	for _, vs := range f.Components {
+		var hasX, hasY bool
+		for _, v := range vs {
+			hasX = hasX || v == x
+			hasY = hasY || v == y
+		}
+		if hasX || hasY {
+			return hasX && hasY
+		}
+	}
+	return false
+}
+
+// TestKruskalForest checks per-component trees of a disconnected graph.
+func TestKruskalForest(t *testing.T) {
+	edges := []Edge[int, int]{{0, 1, 3}, {1, 2, 1}, {0, 2, 2}, {5, 6, 4}}
+	f := Kruskal(edges)
+
+	if len(f.Trees) != 2 || f.Weight != 7 {
+		t.Fatalf("expected 2 trees of total weight 7, got %d trees of weight %d", len(f.Trees), f.Weight)
+	}
+	for root, tree := range f.Trees {
+		if len(tree) != len(f.Components[root])-1 {
+			t.Fatalf("tree of %d has %d edges for %d vertices", root, len(tree), len(f.Components[root]))
+		}
+	}
+	if len(f.Components) != 2 {
+		t.Fatalf("vertices 3 and 4 do not occur and must not be reported, got %v", f.Components)
+	}
+}
+
+// TestKruskalBackendSelection ensures dense ints use compact.DSU while
+// sparse or negative ints and other types use sparse.DSU.
+func TestKruskalBackendSelection(t *testing.T) {
+	if dsu, _ := newDSU([]Edge[int, int]{{0, 3, 1}}, nil); !isDense(dsu) {
+		t.Fatal("expected dense ints to use compact.DSU")
+	}
+	if dsu, _ := newDSU([]Edge[int, int]{{0, 1_000_000, 1}}, nil); isDense(dsu) {
+		t.Fatal("expected sparse ints to use sparse.DSU")
+	}
+	if dsu, _ := newDSU([]Edge[int, int]{{-1, 1, 1}}, nil); isDense(dsu) {
+		t.Fatal("expected negative ints to use sparse.DSU")
+	}
+	if dsu, _ := newDSU(classic, nil); isDense(dsu) {
+		t.Fatal("expected strings to use sparse.DSU")
+	}
+}
+
+// isDense reports whether dsu is backed by compact.DSU.
+func isDense(dsu any) bool {
+	_, ok := dsu.(denseDSU)
+	return ok
+}
+
+// TestKruskalBackendsAgree cross-checks dense and sparse int vertices.
+func TestKruskalBackendsAgree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 200
+	dense := make([]Edge[int, int], 3*n)
+	shifted := make([]Edge[int, int], len(dense))
+	for i := range dense {
+		dense[i] = Edge[int, int]{rng.Intn(n), rng.Intn(n), rng.Intn(50)}
+		shifted[i] = Edge[int, int]{dense[i].U * 1_000_003, dense[i].V * 1_000_003, dense[i].Weight}
+	}
+
+	a, b := Kruskal(dense), Kruskal(shifted)
+	if a.Weight != b.Weight || len(a.Edges) != len(b.Edges) {
+		t.Fatalf("dense weight %d (%d edges), sparse weight %d (%d edges)",
+			a.Weight, len(a.Edges), b.Weight, len(b.Edges))
+	}
+	for i := range a.Edges {
+		if a.Edges[i].U*1_000_003 != b.Edges[i].U || a.Edges[i].V*1_000_003 != b.Edges[i].V {
+			t.Fatalf("edge %d differs between backends", i)
+		}
+	}
+
+	// the forest must span the same components as a plain DSU
+	dsu := sparse.New[int]()
+	for _, e := range dense {
+		dsu.Union(e.U, e.V)
+	}
+	if len(dsu.Groups()) != len(a.Components) {
+		t.Fatalf("expected %d components, got %d", len(dsu.Groups()), len(a.Components))
+	}
+}
@@ -0,0 +1,123 @@
+package parity
+
+import "github.com/arunksaha/gdsu/internal/forest"
+
+// Dense is an incremental GF(2) equation solver over the fixed range
+// [0, n), stored in slices.
+type Dense struct {
+	// parent[x] is the parent of x; if the parent of x is x, then x is the
+	// root of its set. offset[x] is the parity of x relative to its parent.
+	parent []int
+	offset []bool
+
+	// size[r] is the number of elements of the set whose root is r;
+	// unused for other elements.
+	size []int
+
+	// tree is the forest of the constraints that merged sets, each
+	// labeled with itself.
+	tree *forest.Dense[Constraint[int]]
+}
+
+// NewDense creates a Dense solver for elements in the range [0, size).
+func NewDense(size int) *Dense {
+	if size < 0 {
+		size = 0
+	}
+	d := &Dense{
+		parent: make([]int, size),
+		offset: make([]bool, size),
+		size:   make([]int, size),
+		tree:   forest.NewDense[Constraint[int]](size),
+	}
+	for x := range d.parent {
+		d.parent[x] = x
+		d.size[x] = 1
+	}
+	return d
+}
+
+// check panics if x is out of range; method names the caller.
+func (d *Dense) check(x int, method string) {
+	if x < 0 || x >= len(d.parent) {
+		panic("parity.Dense: index out of range in " + method)
+	}
+}
+
+// find returns the root of the set of x and the parity of x relative to
+// it, compressing the path from x to the root.
+func (d *Dense) find(x int) (int, bool) {
+	root := x
+	for d.parent[root] != root {
+		root = d.parent[root]
+	}
+
+	// the parity of x relative to the root is the sum of the offsets on
+	// the path; every element on the path gets its share of the sum
+	total := false
+	for v := x; v != root; v = d.parent[v] {
+		total = total != d.offset[v]
+	}
+	acc := total
+	for v := x; v != root; {
+		next, off := d.parent[v], d.offset[v]
+		d.parent[v], d.offset[v] = root, acc
+		acc = acc != off
+		v = next
+	}
+	return root, total
+}
+
+// Add adds the constraint x xor y = parity. If it contradicts the
+// constraints added so far, Add returns a *ConflictError and ignores it.
+// Panics if x or y are out of range.
+func (d *Dense) Add(x, y int, parity bool) error {
+	d.check(x, "Add")
+	d.check(y, "Add")
+	c := Constraint[int]{X: x, Y: y, Parity: parity}
+	rootX, px := d.find(x)
+	rootY, py := d.find(y)
+	if rootX == rootY {
+		if px != py != parity {
+			return &ConflictError[int]{Constraint: c, Chain: d.tree.Path(x, y)}
+		}
+		return nil
+	}
+
+	// link the root of the smaller set below the other, and hang the
+	// forest tree of the smaller set below the other endpoint of c
+	if d.size[rootX] < d.size[rootY] {
+		rootX, rootY = rootY, rootX
+		x, y = y, x
+	}
+	d.parent[rootY] = rootX
+	d.offset[rootY] = px != py != parity
+	d.size[rootX] += d.size[rootY]
+	d.tree.Link(y, x, c)
+	return nil
+}
+
+// Parity returns the parity x xor y and true if the constraints determine
+// it, and false otherwise.
+// Panics if x or y are out of range.
+func (d *Dense) Parity(x, y int) (bool, bool) {
+	d.check(x, "Parity")
+	d.check(y, "Parity")
+	rootX, px := d.find(x)
+	rootY, py := d.find(y)
+	if rootX != rootY {
+		return false, false
+	}
+	return px != py, true
+}
+
+// Assignment returns an assignment of every element that satisfies all
+// constraints added so far: every root is false, and every other element
+// has its parity relative to its root.
+func (d *Dense) Assignment() []bool {
+	values := make([]bool, len(d.parent))
+	for x := range values {
+		_, values[x] = d.find(x)
+	}
+	return values
+}
@@ -0,0 +1,44 @@
+package parity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDenseRandomSystems(t *testing.T) {
+	testRandomSystems(t,
+		func(n int) solver { return NewDense(n) },
+		func(s solver) func(int) bool {
+			values := s.(*Dense).Assignment()
+			return func(x int) bool { return values[x] }
+		})
+}
+
+func TestDenseLongChain(t *testing.T) {
+	const n = 100_000
+	d := NewDense(n)
+	for i := 1; i < n; i++ {
+		if err := d.Add(i, i-1, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := d.Add(0, n-1, true)
+	var ce *ConflictError[int]
+	if !errors.As(err, &ce) || len(ce.Chain) != n-1 {
+		t.Fatalf("got %v", err)
+	}
+	for i, v := range d.Assignment() {
+		if v {
+			t.Fatalf("element %d is true", i)
+		}
+	}
+}
+
+func TestDensePanicsOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Add did not panic")
+		}
+	}()
+	NewDense(3).Add(0, 3, true)
+}
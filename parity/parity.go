@@ -0,0 +1,175 @@
+// Package parity solves systems of equations x xor y = b over GF(2) as
+// they arrive, with a parity DSU: every element stores its parity relative
+// to its parent, and finding the root composes the parities along the path,
+// so every element knows its parity relative to the root of its set.
+//
+// An equation between two sets merges them; an equation within a set is
+// either implied by the equations so far or contradicts them. To explain a
+// contradiction, the solvers also keep the merging equations as a spanning
+// forest of every set, like sparse.Witness but sharing the set sizes of the
+// DSU, which links by size: the forest path between x and y together with
+// the new equation is an odd cycle, a minimal inconsistent subset, since
+// dropping any of its equations leaves a path, which is always satisfiable.
+//
+// Solver works with any comparable elements and builds on the design of
+// sparse.DSU; Dense works with integers in a fixed range and builds on the
+// design of compact.DSU.
+package parity
+
+import (
+	"fmt"
+
+	"github.com/arunksaha/gdsu/internal/forest"
+)
+
+// Constraint is the equation X xor Y = Parity, where true stands for 1.
+type Constraint[T comparable] struct {
+	X, Y   T
+	Parity bool
+}
+
+// String returns the constraint as x ^ y = b.
+func (c Constraint[T]) String() string {
+	b := 0
+	if c.Parity {
+		b = 1
+	}
+	return fmt.Sprintf("%v ^ %v = %d", c.X, c.Y, b)
+}
+
+// ConflictError reports a constraint that contradicts the constraints
+// added before it.
+type ConflictError[T comparable] struct {
+	// Constraint is the rejected constraint, and Chain the earlier
+	// constraints that connect its elements, in order from X to Y. The
+	// parities of Chain and Constraint add up to 1.
+	Constraint Constraint[T]
+	Chain      []Constraint[T]
+}
+
+func (e *ConflictError[T]) Error() string {
+	return fmt.Sprintf("parity: %v contradicts a chain of %d constraints", e.Constraint, len(e.Chain))
+}
+
+// Solver is an incremental GF(2) equation solver over elements of type T.
+type Solver[T comparable] struct {
+	// parent maps every element to its parent; if the parent of x is x,
+	// then x is the root of its set. offset maps every element whose
+	// parity differs from its parent's to true.
+	parent map[T]T
+	offset map[T]bool
+
+	// size maps the root of every set with more than one element to its
+	// number of elements.
+	size map[T]int
+
+	// tree is the forest of the constraints that merged sets, each
+	// labeled with itself.
+	tree *forest.Map[T, Constraint[T]]
+}
+
+// New creates an empty Solver.
+func New[T comparable]() *Solver[T] {
+	return &Solver[T]{
+		parent: make(map[T]T),
+		offset: make(map[T]bool),
+		size:   make(map[T]int),
+		tree:   forest.NewMap[T, Constraint[T]](nil),
+	}
+}
+
+// sizeOf returns the number of elements of the set whose root is r.
+func (s *Solver[T]) sizeOf(r T) int {
+	if n, ok := s.size[r]; ok {
+		return n
+	}
+	return 1
+}
+
+// find returns the root of the set of x and the parity of x relative to
+// it, adding x as a singleton set if it is not present. It compresses the
+// path from x to the root.
+func (s *Solver[T]) find(x T) (T, bool) {
+	var path []T
+	for {
+		p, ok := s.parent[x]
+		if !ok {
+			s.parent[x] = x
+			break
+		}
+		if p == x {
+			break
+		}
+		path = append(path, x)
+		x = p
+	}
+	root := x
+
+	// walk down from the root, turning offsets relative to the parent
+	// into offsets relative to the root
+	acc := false
+	for i := len(path) - 1; i >= 0; i-- {
+		v := path[i]
+		acc = acc != s.offset[v]
+		s.parent[v] = root
+		if acc {
+			s.offset[v] = true
+		} else {
+			delete(s.offset, v)
+		}
+	}
+	return root, acc
+}
+
+// Add adds the constraint x xor y = parity. If it contradicts the
+// constraints added so far, Add returns a *ConflictError and ignores it.
+func (s *Solver[T]) Add(x, y T, parity bool) error {
+	c := Constraint[T]{X: x, Y: y, Parity: parity}
+	rootX, px := s.find(x)
+	rootY, py := s.find(y)
+	if rootX == rootY {
+		if px != py != parity {
+			return &ConflictError[T]{Constraint: c, Chain: s.tree.Path(x, y)}
+		}
+		return nil
+	}
+
+	// link the root of the smaller set below the other, and hang the
+	// forest tree of the smaller set below the other endpoint of c
+	if s.sizeOf(rootX) < s.sizeOf(rootY) {
+		rootX, rootY = rootY, rootX
+		x, y = y, x
+	}
+	s.parent[rootY] = rootX
+	if px != py != parity {
+		s.offset[rootY] = true
+	}
+	s.size[rootX] = s.sizeOf(rootX) + s.sizeOf(rootY)
+	delete(s.size, rootY)
+	s.tree.Link(y, x, c)
+	return nil
+}
+
+// Parity returns the parity x xor y and true if the constraints determine
+// it, and false otherwise.
+// If x or y did not already exist, then singleton sets are created for them.
+func (s *Solver[T]) Parity(x, y T) (bool, bool) {
+	rootX, px := s.find(x)
+	rootY, py := s.find(y)
+	if rootX != rootY {
+		return false, false
+	}
+	return px != py, true
+}
+
+// Assignment returns an assignment of every element that satisfies all
+// constraints added so far: every root is false, and every other element
+// has its parity relative to its root.
+func (s *Solver[T]) Assignment() map[T]bool {
+	values := make(map[T]bool, len(s.parent))
+	for x := range s.parent {
+		_, p := s.find(x)
+		values[x] = p
+	}
+	return values
+}
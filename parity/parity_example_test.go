@@ -0,0 +1,28 @@
+package parity
+
+import "fmt"
+
+// Example detects a contradiction and explains it by the chain of
+// constraints involved.
+func Example() {
+	s := New[string]()
+	s.Add("a", "b", true)
+	s.Add("b", "c", false)
+	s.Add("c", "d", true)
+
+	p, _ := s.Parity("a", "d")
+	fmt.Println("a ^ d =", p)
+
+	err := s.Add("d", "a", true)
+	fmt.Println(err)
+	for _, c := range err.(*ConflictError[string]).Chain {
+		fmt.Println(" ", c)
+	}
+
+	// Output:
+	// a ^ d = false
+	// parity: d ^ a = 1 contradicts a chain of 3 constraints
+	//   c ^ d = 1
+	//   b ^ c = 0
+	//   a ^ b = 1
+}
@@ -0,0 +1,155 @@
+package parity
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// satisfiable reports whether some assignment of [0, n) satisfies cs, by
+// trying all of them.
+func satisfiable(n int, cs []Constraint[int]) bool {
+	for mask := 0; mask < 1<<n; mask++ {
+		ok := true
+		for _, c := range cs {
+			if (mask>>c.X&1 != mask>>c.Y&1) != c.Parity {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConflict ensures the chain of err connects the elements of the
+// rejected constraint with accepted constraints and forms an odd cycle
+// with it.
+func checkConflict(t *testing.T, err error, accepted map[Constraint[int]]bool) {
+	t.Helper()
+	var ce *ConflictError[int]
+	if !errors.As(err, &ce) {
+		t.Fatalf("got %v, want a *ConflictError", err)
+	}
+	at, sum := ce.Constraint.X, ce.Constraint.Parity
+	for _, c := range ce.Chain {
+		if !accepted[c] {
+			t.Fatalf("chain %v uses %v, which was not accepted", ce.Chain, c)
+		}
+		switch at {
+		case c.X:
+			at = c.Y
+		case c.Y:
+			at = c.X
+		default:
+			t.Fatalf("chain %v is not a walk at %v", ce.Chain, c)
+		}
+		sum = sum != c.Parity
+	}
+	if at != ce.Constraint.Y || !sum {
+		t.Fatalf("chain %v with %v is not an odd cycle", ce.Chain, ce.Constraint)
+	}
+}
+
+// solver is the interface shared by Solver[int] and Dense in the tests.
+type solver interface {
+	Add(x, y int, parity bool) error
+	Parity(x, y int) (bool, bool)
+}
+
+// testRandomSystems adds random constraints and compares every answer with
+// brute force.
+func testRandomSystems(t *testing.T, newSolver func(n int) solver, assignment func(s solver) func(x int) bool) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		n := 2 + rng.Intn(9)
+		s := newSolver(n)
+		var accepted []Constraint[int]
+		inAccepted := make(map[Constraint[int]]bool)
+		for i := 0; i < 3*n; i++ {
+			c := Constraint[int]{X: rng.Intn(n), Y: rng.Intn(n), Parity: rng.Intn(2) == 1}
+			err := s.Add(c.X, c.Y, c.Parity)
+			if want := satisfiable(n, append(accepted, c)); (err == nil) != want {
+				t.Fatalf("trial %d: Add(%v) = %v, want consistent %v", trial, c, err, want)
+			}
+			if err != nil {
+				checkConflict(t, err, inAccepted)
+				continue
+			}
+			accepted = append(accepted, c)
+			inAccepted[c] = true
+
+			x, y := rng.Intn(n), rng.Intn(n)
+			p, known := s.Parity(x, y)
+			flipped := Constraint[int]{X: x, Y: y, Parity: !p}
+			if known && satisfiable(n, append(accepted, flipped)) {
+				t.Fatalf("trial %d: Parity(%d, %d) = %v is not implied", trial, x, y, p)
+			}
+		}
+
+		value := assignment(s)
+		for _, c := range accepted {
+			if (value(c.X) != value(c.Y)) != c.Parity {
+				t.Fatalf("trial %d: assignment violates %v", trial, c)
+			}
+		}
+	}
+}
+
+func TestSolverRandomSystems(t *testing.T) {
+	testRandomSystems(t,
+		func(int) solver { return New[int]() },
+		func(s solver) func(int) bool {
+			values := s.(*Solver[int]).Assignment()
+			return func(x int) bool { return values[x] }
+		})
+}
+
+func TestSolverConflictMessage(t *testing.T) {
+	s := New[string]()
+	s.Add("a", "b", true)
+	s.Add("b", "c", true)
+	err := s.Add("a", "c", true)
+	want := "parity: a ^ c = 1 contradicts a chain of 2 constraints"
+	if err == nil || err.Error() != want {
+		t.Fatalf("got %v, want %q", err, want)
+	}
+	if err := s.Add("c", "a", false); err != nil {
+		t.Fatalf("implied constraint rejected: %v", err)
+	}
+	if _, known := s.Parity("a", "z"); known {
+		t.Fatal("parity of unrelated elements is known")
+	}
+}
+
+func TestSolverLongChain(t *testing.T) {
+	s := New[int]()
+	const n = 100_000
+	for i := 1; i < n; i++ {
+		s.Add(i-1, i, true)
+	}
+	err := s.Add(0, n-1, n%2 == 1)
+	var ce *ConflictError[int]
+	if !errors.As(err, &ce) || len(ce.Chain) != n-1 {
+		t.Fatalf("got %v", err)
+	}
+	if p, _ := s.Parity(0, n-1); p != (n%2 == 0) {
+		t.Fatalf("Parity(0, %d) = %v", n-1, p)
+	}
+}
+
+func TestSolverConflictNearEnd(t *testing.T) {
+	s := New[int]()
+	const n = 100_000
+	for i := 1; i < n; i++ {
+		s.Add(i-1, i, false)
+	}
+	s.Add(n-2, n, false)
+	err := s.Add(n-1, n, true)
+	var ce *ConflictError[int]
+	if !errors.As(err, &ce) || len(ce.Chain) != 2 {
+		t.Fatalf("got %v", err)
+	}
+}
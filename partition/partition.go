@@ -0,0 +1,165 @@
+// Package partition provides the lattice operations on partitions given as
+// DSUs: Join, the finest partition coarser than both arguments, Meet, the
+// coarsest partition finer than both, and the comparisons Refines and
+// Equal, which look only at the classes and not at which elements
+// represent them.
+//
+// The arguments may be any gdsu.DSU values over the same element type, and
+// they may contain different elements: an element missing from one of them
+// counts as a singleton class there. The generic operations read the
+// arguments through Groups, so that they do not add elements to them, and
+// build a sparse.DSU. If both arguments are *compact.DSU, the operations
+// work on the ranges [0, Len()) directly and return a *compact.DSU of the
+// larger range.
+package partition
+
+import (
+	"github.com/arunksaha/gdsu"
+	"github.com/arunksaha/gdsu/compact"
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// bothCompact returns a and b as *compact.DSU if they both are.
+func bothCompact[T comparable](a, b gdsu.DSU[T]) (*compact.DSU, *compact.DSU, bool) {
+	ca, okA := any(a).(*compact.DSU)
+	cb, okB := any(b).(*compact.DSU)
+	return ca, cb, okA && okB
+}
+
+// roots maps every element of d to its root.
+func roots[T comparable](d gdsu.DSU[T]) map[T]T {
+	root := make(map[T]T)
+	for r, members := range d.Groups() {
+		for _, x := range members {
+			root[x] = r
+		}
+	}
+	return root
+}
+
+// Join returns the finest partition that is coarser than both a and b: two
+// elements are in the same class if a chain of classes of a and b, each
+// overlapping the next, connects them.
+func Join[T comparable](a, b gdsu.DSU[T]) gdsu.DSU[T] {
+	if ca, cb, ok := bothCompact(a, b); ok {
+		return any(joinCompact(ca, cb)).(gdsu.DSU[T])
+	}
+	join := sparse.New[T]()
+	for _, d := range []gdsu.DSU[T]{a, b} {
+		for _, members := range d.Groups() {
+			join.UnionAll(members...)
+		}
+	}
+	return join
+}
+
+// joinCompact implements Join for compact DSUs.
+func joinCompact(a, b *compact.DSU) *compact.DSU {
+	if a.Len() < b.Len() {
+		a, b = b, a
+	}
+	join := a.Fork()
+	for x := 0; x < b.Len(); x++ {
+		join.Union(x, b.Find(x))
+	}
+	return join
+}
+
+// Meet returns the coarsest partition that is finer than both a and b: its
+// classes are the nonempty intersections of a class of a with a class of b.
+func Meet[T comparable](a, b gdsu.DSU[T]) gdsu.DSU[T] {
+	if ca, cb, ok := bothCompact(a, b); ok {
+		return any(meetCompact(ca, cb)).(gdsu.DSU[T])
+	}
+	rootA, rootB := roots(a), roots(b)
+	rootOf := func(root map[T]T, x T) T {
+		if r, ok := root[x]; ok {
+			return r
+		}
+		return x
+	}
+
+	meet := sparse.New[T]()
+	first := make(map[[2]T]T)
+	add := func(x T) {
+		key := [2]T{rootOf(rootA, x), rootOf(rootB, x)}
+		if f, ok := first[key]; ok {
+			meet.Union(f, x)
+		} else {
+			first[key] = x
+			meet.Find(x)
+		}
+	}
+	for x := range rootA {
+		add(x)
+	}
+	for x := range rootB {
+		if _, ok := rootA[x]; !ok {
+			add(x)
+		}
+	}
+	return meet
+}
+
+// meetCompact implements Meet for compact DSUs.
+func meetCompact(a, b *compact.DSU) *compact.DSU {
+	n := max(a.Len(), b.Len())
+	meet := compact.New(n)
+	first := make(map[[2]int]int)
+	for x := 0; x < n; x++ {
+		key := [2]int{findOrSelf(a, x), findOrSelf(b, x)}
+		if f, ok := first[key]; ok {
+			meet.Union(f, x)
+		} else {
+			first[key] = x
+		}
+	}
+	return meet
+}
+
+// findOrSelf returns the root of x in d, or x if it is out of range.
+func findOrSelf(d *compact.DSU, x int) int {
+	if x < d.Len() {
+		return d.Find(x)
+	}
+	return x
+}
+
+// Refines reports whether a is finer than or equal to b, that is, whether
+// every class of a lies within a class of b.
+func Refines[T comparable](a, b gdsu.DSU[T]) bool {
+	if ca, cb, ok := bothCompact(a, b); ok {
+		return refinesCompact(ca, cb)
+	}
+	rootB := roots(b)
+	for _, members := range a.Groups() {
+		r, ok := rootB[members[0]]
+		for _, x := range members[1:] {
+			if s, in := rootB[x]; !ok || !in || s != r {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// refinesCompact implements Refines for compact DSUs.
+func refinesCompact(a, b *compact.DSU) bool {
+	// image[r] is the root in b of the elements whose root in a is r, plus
+	// one, or 0 if no such element has been seen yet
+	image := make([]int, a.Len())
+	for x := 0; x < a.Len(); x++ {
+		r, s := a.Find(x), findOrSelf(b, x)+1
+		if image[r] == 0 {
+			image[r] = s
+		} else if image[r] != s {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal reports whether a and b have the same classes.
+func Equal[T comparable](a, b gdsu.DSU[T]) bool {
+	return Refines(a, b) && Refines(b, a)
+}
@@ -0,0 +1,24 @@
+package partition
+
+import (
+	"fmt"
+
+	"github.com/arunksaha/gdsu/compact"
+)
+
+// Example combines two clusterings of six elements.
+func Example() {
+	a := compact.New(6)
+	a.Union(0, 1)
+	a.Union(2, 3)
+	b := compact.New(6)
+	b.Union(1, 2)
+	b.Union(0, 1)
+
+	fmt.Println(len(Join(a, b).Groups()), len(Meet(a, b).Groups()))
+	fmt.Println(Refines(Meet(a, b), a), Refines(a, b), Equal(Join(a, b), Join(b, a)))
+
+	// Output:
+	// 3 5
+	// true false true
+}
@@ -0,0 +1,132 @@
+package partition
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/arunksaha/gdsu"
+	"github.com/arunksaha/gdsu/compact"
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// randomPairs returns random unions over [0, n).
+func randomPairs(rng *rand.Rand, n, k int) [][2]int {
+	pairs := make([][2]int, k)
+	for i := range pairs {
+		pairs[i] = [2]int{rng.Intn(n), rng.Intn(n)}
+	}
+	return pairs
+}
+
+// build returns a DSU of the given kind over [0, n) with the given unions.
+func build(kind string, n int, pairs [][2]int) gdsu.DSU[int] {
+	var d gdsu.BatchDSU[int]
+	if kind == "compact" {
+		d = compact.New(n)
+	} else {
+		s := sparse.New[int]()
+		for x := 0; x < n; x++ {
+			s.Find(x)
+		}
+		d = s
+	}
+	d.UnionPairs(pairs)
+	return d
+}
+
+// same reports whether x and y are in the same class of d, counting
+// elements missing from d as singletons.
+func same(d gdsu.DSU[int], n, x, y int) bool {
+	if x == y {
+		return true
+	}
+	if x >= n || y >= n {
+		return false
+	}
+	return d.Connected(x, y)
+}
+
+// TestLatticeMatchesBruteForce compares every operation with brute force
+// for all combinations of compact and sparse inputs of different sizes.
+func TestLatticeMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	kinds := []string{"compact", "sparse"}
+	for trial := 0; trial < 200; trial++ {
+		na, nb := 1+rng.Intn(30), 1+rng.Intn(30)
+		pa, pb := randomPairs(rng, na, rng.Intn(2*na)), randomPairs(rng, nb, rng.Intn(2*nb))
+		if rng.Intn(4) == 0 {
+			nb, pb = na, pa[:rng.Intn(len(pa)+1)] // b refines a
+		}
+		n := max(na, nb)
+
+		// brute-force join: transitive closure of both relations
+		joinRef := compact.New(n)
+		for _, p := range append(append([][2]int{}, pa...), pb...) {
+			joinRef.Union(p[0], p[1])
+		}
+
+		for _, ka := range kinds {
+			for _, kb := range kinds {
+				a, b := build(ka, na, pa), build(kb, nb, pb)
+				join, meet := Join(a, b), Meet(a, b)
+				_, isCompact := join.(*compact.DSU)
+				if isCompact != (ka == "compact" && kb == "compact") {
+					t.Fatalf("trial %d: Join(%s, %s) returned %T", trial, ka, kb, join)
+				}
+
+				aRefinesB, bRefinesA := true, true
+				for x := 0; x < n; x++ {
+					for y := 0; y < n; y++ {
+						inA, inB := same(a, na, x, y), same(b, nb, x, y)
+						if join.Connected(x, y) != joinRef.Connected(x, y) {
+							t.Fatalf("trial %d (%s, %s): Join wrong at %d, %d", trial, ka, kb, x, y)
+						}
+						if meet.Connected(x, y) != (inA && inB) {
+							t.Fatalf("trial %d (%s, %s): Meet wrong at %d, %d", trial, ka, kb, x, y)
+						}
+						if inA && !inB {
+							aRefinesB = false
+						}
+						if inB && !inA {
+							bRefinesA = false
+						}
+					}
+				}
+				if Refines(a, b) != aRefinesB || Refines(b, a) != bRefinesA {
+					t.Fatalf("trial %d (%s, %s): Refines = %v, %v; want %v, %v",
+						trial, ka, kb, Refines(a, b), Refines(b, a), aRefinesB, bRefinesA)
+				}
+				if Equal(a, b) != (aRefinesB && bRefinesA) {
+					t.Fatalf("trial %d (%s, %s): Equal wrong", trial, ka, kb)
+				}
+				if !Refines(meet, a) || !Refines(meet, b) || !Refines(a, join) || !Refines(b, join) {
+					t.Fatalf("trial %d (%s, %s): lattice order violated", trial, ka, kb)
+				}
+			}
+		}
+	}
+}
+
+func TestEqualIgnoresRepresentatives(t *testing.T) {
+	a, b := sparse.New[string](), sparse.New[string]()
+	a.Union("x", "y")
+	a.Union("y", "z")
+	b.Union("z", "y")
+	b.Union("x", "z")
+	if a.Find("x") == b.Find("x") {
+		t.Fatal("test needs partitions with different representatives")
+	}
+	if !Equal[string](a, b) {
+		t.Fatal("equal partitions with different representatives differ")
+	}
+}
+
+func TestOperationsDoNotAddElements(t *testing.T) {
+	a, b := sparse.New[int](1, 2), sparse.New[int](3)
+	Join[int](a, b)
+	Meet[int](a, b)
+	Refines[int](a, b)
+	if len(a.Groups()) != 2 || len(b.Groups()) != 1 {
+		t.Fatal("arguments gained elements")
+	}
+}
@@ -0,0 +1,264 @@
+// Package pointsto implements Steensgaard's unification-based points-to
+// analysis (Steensgaard, 1996) over a small SSA-like IR of assignments,
+// address-of, loads, stores and calls.
+//
+// Every variable is an abstract memory location, and locations that a
+// pointer may point to are merged into equivalence classes, the sets of a
+// sparse.DSU. Every class has at most one pointee class, so the points-to
+// graph is a function on classes: an assignment x = y, which makes x point
+// wherever y points, unifies the pointee classes of x and y, and so, in
+// turn, their pointees, and so on. A class that has to have a pointee
+// before any location is known to be in it gets a fresh, anonymous
+// location. Functions are locations with a signature, the locations of
+// their parameters and result; a call assigns the arguments to the
+// parameters and the result to its destination, and unifying two
+// functions unifies their parameters and results.
+//
+// The analysis is flow- and context-insensitive, and each statement takes
+// almost constant amortized time, so it scales to whole programs at the
+// price of precision.
+package pointsto
+
+import (
+	"slices"
+
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// signature is the signature of a class of functions: the locations of
+// their parameters and result.
+type signature struct {
+	params []int
+	result int
+}
+
+// Analysis is a points-to analysis of statements over variables of type T.
+// Statements may be added in any order.
+type Analysis[T comparable] struct {
+	dsu *sparse.DSU[int]
+
+	// ids maps every variable to its location and names maps back;
+	// locations without a name are anonymous.
+	ids   map[T]int
+	names map[int]T
+	next  int
+
+	// pointee maps the root of every class with a pointee to a location in
+	// the pointee class, sig the root of every class of functions to their
+	// signature, and members the root of every class with variables to
+	// their locations.
+	pointee map[int]int
+	sig     map[int]*signature
+	members map[int][]int
+}
+
+// New creates an empty Analysis.
+func New[T comparable]() *Analysis[T] {
+	return &Analysis[T]{
+		dsu:     sparse.New[int](),
+		ids:     make(map[T]int),
+		names:   make(map[int]T),
+		pointee: make(map[int]int),
+		sig:     make(map[int]*signature),
+		members: make(map[int][]int),
+	}
+}
+
+// loc returns the location of the variable v, creating it if needed.
+func (a *Analysis[T]) loc(v T) int {
+	if id, ok := a.ids[v]; ok {
+		return id
+	}
+	id := a.fresh()
+	a.ids[v] = id
+	a.names[id] = v
+	a.members[id] = []int{id}
+	return id
+}
+
+// fresh returns a new anonymous location.
+func (a *Analysis[T]) fresh() int {
+	id := a.next
+	a.next++
+	a.dsu.Find(id)
+	return id
+}
+
+// deref returns a location in the pointee class of the class of x, giving
+// it a fresh pointee if it has none.
+func (a *Analysis[T]) deref(x int) int {
+	r := a.dsu.Find(x)
+	if p, ok := a.pointee[r]; ok {
+		return p
+	}
+	p := a.fresh()
+	a.pointee[r] = p
+	return p
+}
+
+// signatureOf returns the signature of the class of the function f, giving
+// it one with fresh locations if it has none, and extending it to at least
+// n parameters.
+func (a *Analysis[T]) signatureOf(f, n int) *signature {
+	r := a.dsu.Find(f)
+	s, ok := a.sig[r]
+	if !ok {
+		s = &signature{result: a.fresh()}
+		a.sig[r] = s
+	}
+	for len(s.params) < n {
+		s.params = append(s.params, a.fresh())
+	}
+	return s
+}
+
+// join unifies the classes of x and y, and then their pointees and
+// signatures, recursively.
+func (a *Analysis[T]) join(x, y int) {
+	work := [][2]int{{x, y}}
+	for len(work) > 0 {
+		p := work[len(work)-1]
+		work = work[:len(work)-1]
+		rx, ry := a.dsu.Find(p[0]), a.dsu.Find(p[1])
+		if rx == ry {
+			continue
+		}
+
+		px, hasPX := a.pointee[rx]
+		py, hasPY := a.pointee[ry]
+		sx, hasSX := a.sig[rx]
+		sy, hasSY := a.sig[ry]
+		mx, my := a.members[rx], a.members[ry]
+		for _, r := range []int{rx, ry} {
+			delete(a.pointee, r)
+			delete(a.sig, r)
+			delete(a.members, r)
+		}
+
+		a.dsu.Union(rx, ry)
+		r := a.dsu.Find(rx)
+		switch {
+		case hasPX && hasPY:
+			a.pointee[r] = px
+			work = append(work, [2]int{px, py})
+		case hasPX:
+			a.pointee[r] = px
+		case hasPY:
+			a.pointee[r] = py
+		}
+		switch {
+		case hasSX && hasSY:
+			if len(sx.params) < len(sy.params) {
+				sx, sy = sy, sx
+			}
+			a.sig[r] = sx
+			for i, q := range sy.params {
+				work = append(work, [2]int{sx.params[i], q})
+			}
+			work = append(work, [2]int{sx.result, sy.result})
+		case hasSX:
+			a.sig[r] = sx
+		case hasSY:
+			a.sig[r] = sy
+		}
+		if len(mx) < len(my) {
+			mx, my = my, mx
+		}
+		if m := append(mx, my...); len(m) > 0 {
+			a.members[r] = m
+		}
+	}
+}
+
+// Assign adds the statement dst = src.
+func (a *Analysis[T]) Assign(dst, src T) {
+	a.join(a.deref(a.loc(dst)), a.deref(a.loc(src)))
+}
+
+// AddressOf adds the statement dst = &x. Allocations, such as
+// dst = new(T), are address-of statements of a variable naming the
+// allocation site.
+func (a *Analysis[T]) AddressOf(dst, x T) {
+	a.join(a.deref(a.loc(dst)), a.loc(x))
+}
+
+// Load adds the statement dst = *src.
+func (a *Analysis[T]) Load(dst, src T) {
+	a.join(a.deref(a.loc(dst)), a.deref(a.deref(a.loc(src))))
+}
+
+// Store adds the statement *dst = src.
+func (a *Analysis[T]) Store(dst, src T) {
+	a.join(a.deref(a.deref(a.loc(dst))), a.deref(a.loc(src)))
+}
+
+// Func declares the function f with the given parameters and result
+// variable; return statements are assignments to result. A function value
+// is taken with AddressOf(dst, f).
+func (a *Analysis[T]) Func(f T, params []T, result T) {
+	s := &signature{result: a.loc(result)}
+	for _, p := range params {
+		s.params = append(s.params, a.loc(p))
+	}
+	id := a.fresh()
+	a.sig[id] = s
+	a.join(id, a.loc(f))
+}
+
+// Call adds the statement dst = f(args...), a direct call of the function
+// f. Extra arguments beyond the parameters of f are ignored.
+func (a *Analysis[T]) Call(dst, f T, args ...T) {
+	a.call(dst, a.loc(f), args)
+}
+
+// CallIndirect adds the statement dst = (*fp)(args...), a call through the
+// function pointer fp.
+func (a *Analysis[T]) CallIndirect(dst, fp T, args ...T) {
+	a.call(dst, a.deref(a.loc(fp)), args)
+}
+
+// call assigns args to the parameters of the function f and its result to
+// dst.
+func (a *Analysis[T]) call(dst T, f int, args []T) {
+	s := a.signatureOf(f, len(args))
+	params, result := slices.Clone(s.params), s.result
+	for i, arg := range args {
+		a.join(a.deref(params[i]), a.deref(a.loc(arg)))
+	}
+	a.join(a.deref(a.loc(dst)), a.deref(result))
+}
+
+// PointsTo returns the variables that p may point to, in order of first
+// mention.
+func (a *Analysis[T]) PointsTo(p T) []T {
+	id, ok := a.ids[p]
+	if !ok {
+		return nil
+	}
+	pt, ok := a.pointee[a.dsu.Find(id)]
+	if !ok {
+		return nil
+	}
+	locs := slices.Clone(a.members[a.dsu.Find(pt)])
+	slices.Sort(locs)
+	vars := make([]T, len(locs))
+	for i, l := range locs {
+		vars[i] = a.names[l]
+	}
+	return vars
+}
+
+// MayAlias reports whether p and q may point to the same location, that is,
+// whether their pointees are in the same class. Two pointers that have only
+// been assigned to each other alias even if neither points to a known
+// variable.
+func (a *Analysis[T]) MayAlias(p, q T) bool {
+	ip, okP := a.ids[p]
+	iq, okQ := a.ids[q]
+	if !okP || !okQ {
+		return false
+	}
+	pp, okP := a.pointee[a.dsu.Find(ip)]
+	pq, okQ := a.pointee[a.dsu.Find(iq)]
+	return okP && okQ && a.dsu.Connected(pp, pq)
+}
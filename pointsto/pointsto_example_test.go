@@ -0,0 +1,26 @@
+package pointsto
+
+import "fmt"
+
+// Example analyzes
+//
+//	p := &x
+//	q := &y
+//	r := p
+//	*r = q
+//	s := *p
+func Example() {
+	a := New[string]()
+	a.AddressOf("p", "x")
+	a.AddressOf("q", "y")
+	a.Assign("r", "p")
+	a.Store("r", "q")
+	a.Load("s", "p")
+
+	fmt.Println(a.PointsTo("r"), a.PointsTo("x"), a.PointsTo("s"))
+	fmt.Println(a.MayAlias("p", "r"), a.MayAlias("q", "s"), a.MayAlias("p", "q"))
+
+	// Output:
+	// [x] [y] [y]
+	// true true false
+}
@@ -0,0 +1,200 @@
+package pointsto
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestBasicStatements(t *testing.T) {
+	a := New[string]()
+	a.AddressOf("p", "x") // p = &x
+	a.AddressOf("q", "y") // q = &y
+	a.Assign("r", "p")    // r = p
+	a.AddressOf("pp", "p")
+	a.Load("s", "pp") // s = *pp
+	a.AddressOf("u", "z")
+	a.Store("pp", "u") // *pp = u
+
+	if got := a.PointsTo("p"); !slices.Equal(got, []string{"x", "z"}) {
+		t.Fatalf("PointsTo(p) = %v", got)
+	}
+	if got := a.PointsTo("q"); !slices.Equal(got, []string{"y"}) {
+		t.Fatalf("PointsTo(q) = %v", got)
+	}
+	if got := a.PointsTo("pp"); !slices.Equal(got, []string{"p"}) {
+		t.Fatalf("PointsTo(pp) = %v", got)
+	}
+	for _, v := range []string{"r", "s", "u"} {
+		if !a.MayAlias("p", v) {
+			t.Fatalf("p and %s do not alias", v)
+		}
+	}
+	if a.MayAlias("p", "q") || a.MayAlias("p", "x") || a.MayAlias("p", "unknown") {
+		t.Fatal("unexpected alias")
+	}
+	if a.PointsTo("x") != nil || a.PointsTo("unknown") != nil {
+		t.Fatal("non-pointers point somewhere")
+	}
+}
+
+// TestUnificationIsSymmetric checks the characteristic imprecision of
+// Steensgaard's analysis: assigning q to p merges what both point to.
+func TestUnificationIsSymmetric(t *testing.T) {
+	a := New[string]()
+	a.AddressOf("p", "x")
+	a.AddressOf("q", "y")
+	a.Assign("p", "q")
+	if got := a.PointsTo("q"); !slices.Equal(got, []string{"x", "y"}) {
+		t.Fatalf("PointsTo(q) = %v", got)
+	}
+}
+
+func TestCalls(t *testing.T) {
+	a := New[string]()
+	// func id(a) r { r = a }
+	a.Func("id", []string{"id.a"}, "id.r")
+	a.Assign("id.r", "id.a")
+
+	a.AddressOf("p", "x")
+	a.Call("q", "id", "p")
+	if got := a.PointsTo("q"); !slices.Equal(got, []string{"x"}) {
+		t.Fatalf("PointsTo(q) = %v", got)
+	}
+
+	// calls through a pointer before the target is known
+	a.AddressOf("s", "y")
+	a.CallIndirect("t", "fp", "s")
+	a.Func("store", []string{"store.dst", "store.v"}, "store.r")
+	a.Store("store.dst", "store.v")
+	a.AddressOf("fp", "id")
+	if got := a.PointsTo("t"); !slices.Equal(got, []string{"x", "y"}) {
+		t.Fatalf("PointsTo(t) = %v", got)
+	}
+	if got := a.PointsTo("fp"); !slices.Equal(got, []string{"id"}) {
+		t.Fatalf("PointsTo(fp) = %v", got)
+	}
+
+	// making fp also point to store unifies the two signatures
+	a.AddressOf("fp", "store")
+	a.AddressOf("w", "cell")
+	a.CallIndirect("_", "fp", "w", "p")
+	if got := a.PointsTo("w"); !slices.Contains(got, "cell") || !slices.Contains(got, "x") {
+		t.Fatalf("PointsTo(w) = %v", got)
+	}
+	if got := a.PointsTo("cell"); !slices.Contains(got, "x") {
+		t.Fatalf("PointsTo(cell) = %v", got)
+	}
+}
+
+// andersen computes inclusion-based points-to sets, which are at least as
+// precise as Steensgaard's, by iterating the statements to a fixed point.
+func andersen(stmts []stmt) map[string]map[string]bool {
+	pts := make(map[string]map[string]bool)
+	add := func(p, x string) bool {
+		if pts[p] == nil {
+			pts[p] = make(map[string]bool)
+		}
+		if pts[p][x] {
+			return false
+		}
+		pts[p][x] = true
+		return true
+	}
+	copyInto := func(dst, src string) bool {
+		changed := false
+		for x := range pts[src] {
+			changed = add(dst, x) || changed
+		}
+		return changed
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, s := range stmts {
+			switch s.op {
+			case "addr":
+				changed = add(s.dst, s.src) || changed
+			case "assign":
+				changed = copyInto(s.dst, s.src) || changed
+			case "load":
+				for x := range pts[s.src] {
+					changed = copyInto(s.dst, x) || changed
+				}
+			case "store":
+				for x := range pts[s.dst] {
+					changed = copyInto(x, s.src) || changed
+				}
+			}
+		}
+	}
+	return pts
+}
+
+type stmt struct {
+	op       string
+	dst, src string
+}
+
+// TestSoundAgainstAndersen checks on random programs that every points-to
+// fact found by inclusion-based analysis is also found by unification.
+func TestSoundAgainstAndersen(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ops := []string{"addr", "assign", "load", "store"}
+	for trial := 0; trial < 200; trial++ {
+		nvars := 3 + rng.Intn(8)
+		var stmts []stmt
+		for i := 0; i < 1+rng.Intn(15); i++ {
+			stmts = append(stmts, stmt{
+				op:  ops[rng.Intn(len(ops))],
+				dst: fmt.Sprint("v", rng.Intn(nvars)),
+				src: fmt.Sprint("v", rng.Intn(nvars)),
+			})
+		}
+
+		a := New[string]()
+		for _, s := range stmts {
+			switch s.op {
+			case "addr":
+				a.AddressOf(s.dst, s.src)
+			case "assign":
+				a.Assign(s.dst, s.src)
+			case "load":
+				a.Load(s.dst, s.src)
+			case "store":
+				a.Store(s.dst, s.src)
+			}
+		}
+
+		pts := andersen(stmts)
+		for p, targets := range pts {
+			got := a.PointsTo(p)
+			for x := range targets {
+				if !slices.Contains(got, x) {
+					t.Fatalf("trial %d: PointsTo(%s) = %v misses %s; program %v", trial, p, got, x, stmts)
+				}
+			}
+			for q, other := range pts {
+				for x := range targets {
+					if other[x] && !a.MayAlias(p, q) {
+						t.Fatalf("trial %d: %s and %s both point to %s but do not alias", trial, p, q, x)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLongChain(t *testing.T) {
+	a := New[int]()
+	const n = 100_000
+	for i := 0; i < n; i++ {
+		a.AddressOf(i, (i+n-1)%n)
+	}
+	// making neighbouring pointers of the cycle alias unifies the whole cycle,
+	// one pointee at a time
+	a.Assign(1, 0)
+	if !a.MayAlias(n-1, 1) || !a.MayAlias(0, 2) || len(a.PointsTo(1)) != n {
+		t.Fatalf("got %d pointees", len(a.PointsTo(1)))
+	}
+}
@@ -0,0 +1,149 @@
+// Package proof provides a proof-producing union-find in the style of
+// Nieuwenhuis and Oliveras (2005): every union carries a caller-supplied
+// reason, and the DSU can explain why two elements are equal by the
+// reasons of the unions that connect them.
+//
+// Alongside a sparse.DSU, which answers Find and Connected, the DSU keeps a
+// proof forest: every successful union adds an edge labeled with its reason
+// between its two arguments, after rerooting the smaller of the two proof
+// trees at its argument. The explanation of x == y consists of the reasons
+// on the proof-forest path between x and y. It is irredundant: the path is
+// the only one between x and y in the forest, so dropping any reason leaves
+// x and y unconnected by the rest. Unions that do not merge are redundant
+// and never become part of an explanation.
+//
+// Explaining climbs from x and y to their nearest common ancestor, taking
+// time linear in the explanation size. ExplainAll explains several
+// equalities at once and uses an auxiliary union-find over the explained
+// edges to emit every reason only once, as the original algorithm does for
+// the pending equalities of congruence closure.
+package proof
+
+import (
+	"slices"
+
+	"github.com/arunksaha/gdsu/internal/forest"
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// DSU is a proof-producing Disjoint-Set Union with reasons of type R.
+type DSU[T comparable, R any] struct {
+	dsu *sparse.DSU[T]
+
+	// size maps the root of every set of dsu with more than one element to
+	// its number of elements.
+	size map[T]int
+
+	// proof is the proof forest, whose edges are labeled with reasons.
+	proof *forest.Map[T, R]
+}
+
+// New creates an empty DSU.
+func New[T comparable, R any]() *DSU[T, R] {
+	return &DSU[T, R]{
+		dsu:   sparse.New[T](),
+		size:  make(map[T]int),
+		proof: forest.NewMap[T, R](nil),
+	}
+}
+
+// sizeOf returns the number of elements of the set whose root is r.
+func (d *DSU[T, R]) sizeOf(r T) int {
+	if s, ok := d.size[r]; ok {
+		return s
+	}
+	return 1
+}
+
+// Find returns the representative element (root) of the set containing x.
+// If x is not present, it is added as a singleton set.
+func (d *DSU[T, R]) Find(x T) T {
+	return d.dsu.Find(x)
+}
+
+// Connected reports whether x and y are in the same set.
+// If x or y did not already exist, then singleton sets are created for them.
+func (d *DSU[T, R]) Connected(x, y T) bool {
+	return d.dsu.Connected(x, y)
+}
+
+// Union merges the sets containing x and y because of reason.
+// Returns true if the sets were separate and are now merged; otherwise the
+// union is redundant and reason is discarded.
+func (d *DSU[T, R]) Union(x, y T, reason R) bool {
+	rootX, rootY := d.dsu.Find(x), d.dsu.Find(y)
+	if rootX == rootY {
+		return false
+	}
+
+	// reverse the path to the root of the smaller proof tree
+	if d.sizeOf(rootX) > d.sizeOf(rootY) {
+		x, y = y, x
+	}
+	d.proof.Link(x, y, reason)
+
+	total := d.sizeOf(rootX) + d.sizeOf(rootY)
+	delete(d.size, rootX)
+	delete(d.size, rootY)
+	d.dsu.Union(rootX, rootY)
+	d.size[d.dsu.Find(rootX)] = total
+	return true
+}
+
+// Explain returns the reasons of the unions that connect x and y, in the
+// order in which they occur on the path from x to y, and true. If x and y
+// are not connected, Explain returns false. If x or y did not already
+// exist, then singleton sets are created for them.
+func (d *DSU[T, R]) Explain(x, y T) ([]R, bool) {
+	return d.ExplainAll([][2]T{{x, y}})
+}
+
+// ExplainAll returns the reasons of the unions that connect the elements of
+// every pair, and true. Every reason occurs once, even if it is needed for
+// several pairs. If the elements of some pair are not connected,
+// ExplainAll returns false. If an element did not already exist, then a
+// singleton set is created for it.
+func (d *DSU[T, R]) ExplainAll(pairs [][2]T) ([]R, bool) {
+	for _, p := range pairs {
+		if !d.dsu.Connected(p[0], p[1]) {
+			return nil, false
+		}
+	}
+
+	// explained unites the elements joined by the edges explained so far,
+	// which form subtrees of the proof forest; highest maps the root of
+	// every such set with more than one element to its topmost element.
+	explained := sparse.New[T]()
+	highest := make(map[T]T)
+	var reasons []R
+
+	// along appends the unexplained reasons on the path from x up to its
+	// ancestor c, in order from x
+	along := func(x, c T) {
+		for explained.Find(x) != explained.Find(c) {
+			if top, ok := highest[explained.Find(x)]; ok {
+				x = top
+			}
+			p, reason, _ := d.proof.Parent(x)
+			reasons = append(reasons, reason)
+			top := p
+			if t, ok := highest[explained.Find(p)]; ok {
+				top = t
+			}
+			delete(highest, explained.Find(x))
+			delete(highest, explained.Find(p))
+			explained.Union(x, p)
+			highest[explained.Find(p)] = top
+			x = p
+		}
+	}
+
+	for _, p := range pairs {
+		c, _, _ := d.proof.Meet(p[0], p[1])
+		along(p[0], c)
+		start := len(reasons)
+		along(p[1], c)
+		slices.Reverse(reasons[start:])
+	}
+	return reasons, true
+}
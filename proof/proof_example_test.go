@@ -0,0 +1,24 @@
+package proof
+
+import "fmt"
+
+// Example explains a derived equality by the input equalities it rests on.
+func Example() {
+	d := New[string, string]()
+	d.Union("x", "y", "assert x = y")
+	d.Union("z", "w", "assert z = w")
+	d.Union("y", "z", "assert y = z")
+	d.Union("x", "w", "assert x = w") // redundant
+
+	fmt.Println(d.Connected("x", "w"))
+	reasons, _ := d.Explain("x", "w")
+	for _, reason := range reasons {
+		fmt.Println(reason)
+	}
+
+	// Output:
+	// true
+	// assert x = y
+	// assert y = z
+	// assert z = w
+}
@@ -0,0 +1,138 @@
+package proof
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// connects reports whether the unions with the given reasons, except for
+// skip, connect every pair.
+func connects(unions [][2]int, reasons []int, skip int, pairs [][2]int) bool {
+	dsu := sparse.New[int]()
+	for i, r := range reasons {
+		if i != skip {
+			dsu.Union(unions[r][0], unions[r][1])
+		}
+	}
+	for _, p := range pairs {
+		if !dsu.Connected(p[0], p[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// TestExplainMatchesNaive checks on random unions that explanations walk
+// from x to y, justify the equality, and are irredundant.
+func TestExplainMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		n := 2 + rng.Intn(60)
+		d := New[int, int]()
+		var unions [][2]int
+		for i := 0; i < 2*n; i++ {
+			x, y := rng.Intn(n), rng.Intn(n)
+			unions = append(unions, [2]int{x, y})
+			d.Union(x, y, i)
+
+			a, b := rng.Intn(n), rng.Intn(n)
+			if !d.Connected(a, b) {
+				continue
+			}
+			reasons, ok := d.Explain(a, b)
+			if !ok {
+				t.Fatalf("trial %d: Explain(%d, %d) reports connected elements as unconnected", trial, a, b)
+			}
+			at := a
+			for _, r := range reasons {
+				switch at {
+				case unions[r][0]:
+					at = unions[r][1]
+				case unions[r][1]:
+					at = unions[r][0]
+				default:
+					t.Fatalf("trial %d: Explain(%d, %d) = %v is not a walk", trial, a, b, reasons)
+				}
+			}
+			if at != b {
+				t.Fatalf("trial %d: Explain(%d, %d) = %v ends at %d", trial, a, b, reasons, at)
+			}
+			for skip := range reasons {
+				if connects(unions, reasons, skip, [][2]int{{a, b}}) {
+					t.Fatalf("trial %d: Explain(%d, %d) = %v is redundant", trial, a, b, reasons)
+				}
+			}
+		}
+	}
+}
+
+// TestExplainAllDeduplicates checks that a batch explanation justifies all
+// pairs and lists every reason once.
+func TestExplainAllDeduplicates(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	const n = 300
+	d := New[int, int]()
+	var unions [][2]int
+	for i := 0; i < n; i++ {
+		x, y := rng.Intn(n), rng.Intn(n)
+		unions = append(unions, [2]int{x, y})
+		d.Union(x, y, i)
+	}
+	var pairs [][2]int
+	for len(pairs) < 50 {
+		if a, b := rng.Intn(n), rng.Intn(n); d.Connected(a, b) {
+			pairs = append(pairs, [2]int{a, b})
+		}
+	}
+
+	reasons, ok := d.ExplainAll(pairs)
+	if !ok {
+		t.Fatal("ExplainAll reports connected elements as unconnected")
+	}
+	seen := make(map[int]bool)
+	for _, r := range reasons {
+		if seen[r] {
+			t.Fatalf("reason %d repeated in %v", r, reasons)
+		}
+		seen[r] = true
+	}
+	if !connects(unions, reasons, -1, pairs) {
+		t.Fatalf("reasons %v do not justify %v", reasons, pairs)
+	}
+}
+
+// TestExplainDeepChain explains the ends of a long chain of equalities.
+func TestExplainDeepChain(t *testing.T) {
+	const n = 100_000
+	d := New[int, int]()
+	for x := 1; x < n; x++ {
+		d.Union(x-1, x, x)
+	}
+	if got, _ := d.Explain(n-1, 0); len(got) != n-1 || got[0] != n-1 || got[n-2] != 1 {
+		t.Fatalf("unexpected explanation of length %d", len(got))
+	}
+	if got, _ := d.Explain(5, 7); len(got) != 2 || got[0] != 6 || got[1] != 7 {
+		t.Fatalf("Explain(5, 7) = %v, want [6 7]", got)
+	}
+	if got, _ := d.Explain(3, 3); len(got) != 0 {
+		t.Fatalf("Explain(3, 3) = %v, want none", got)
+	}
+}
+
+// TestExplainNotConnected ensures explaining unconnected elements reports
+// false.
+func TestExplainNotConnected(t *testing.T) {
+	d := New[string, string]()
+	d.Union("a", "b", "a=b")
+	if d.Union("b", "a", "b=a") || d.Find("a") != d.Find("b") {
+		t.Fatalf("expected the second union to be redundant")
+	}
+	if reasons, ok := d.Explain("a", "c"); ok || reasons != nil {
+		t.Fatalf("Explain(a, c) = %v, %v, want nil, false", reasons, ok)
+	}
+	if reasons, ok := d.ExplainAll([][2]string{{"a", "b"}, {"b", "c"}}); ok || reasons != nil {
+		t.Fatalf("ExplainAll = %v, %v, want nil, false", reasons, ok)
+	}
+}
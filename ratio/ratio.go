@@ -0,0 +1,197 @@
+// Package ratio infers conversion factors between units from asserted
+// ratios, such as 1 box = 12 units or 1 USD = 0.92 EUR, with a DSU of
+// multiplicative potentials.
+//
+// Every element stores its ratio to its parent, so the ratio of an element
+// to the root of its set is the product of the ratios on the path, and the
+// ratio between two elements of a set is the quotient of their ratios to
+// the root. Lookups compress paths in two passes like sparse.DSU.Find:
+// the first pass finds the root and the product of the path, and the
+// second points every element on the path at the root with its share of
+// the product. An assertion between two sets merges them; an assertion within
+// a set is checked against the implied ratio.
+//
+// The arithmetic is pluggable: Float compares float64 ratios with a
+// relative tolerance, and Rat computes exactly with *big.Rat.
+package ratio
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Arith is the arithmetic of ratios of type R.
+type Arith[R any] interface {
+	// One returns the ratio 1.
+	One() R
+
+	// Mul returns a * b and Quo returns a / b, without modifying a or b.
+	Mul(a, b R) R
+	Quo(a, b R) R
+
+	// Equal reports whether a and b are equal, or close enough.
+	Equal(a, b R) bool
+
+	// Valid reports whether r can be asserted: it must be nonzero and,
+	// for floats, finite.
+	Valid(r R) bool
+}
+
+// Float returns float64 arithmetic that considers two ratios equal if they
+// differ by at most tol times the larger of their magnitudes.
+func Float(tol float64) Arith[float64] {
+	return floatArith{tol: tol}
+}
+
+type floatArith struct {
+	tol float64
+}
+
+func (floatArith) One() float64             { return 1 }
+func (floatArith) Mul(a, b float64) float64 { return a * b }
+func (floatArith) Quo(a, b float64) float64 { return a / b }
+
+func (f floatArith) Equal(a, b float64) bool {
+	return math.Abs(a-b) <= f.tol*max(math.Abs(a), math.Abs(b))
+}
+
+func (floatArith) Valid(r float64) bool {
+	return r != 0 && !math.IsInf(r, 0) && !math.IsNaN(r)
+}
+
+// Rat returns exact arithmetic on *big.Rat.
+func Rat() Arith[*big.Rat] {
+	return ratArith{}
+}
+
+type ratArith struct{}
+
+func (ratArith) One() *big.Rat              { return big.NewRat(1, 1) }
+func (ratArith) Mul(a, b *big.Rat) *big.Rat { return new(big.Rat).Mul(a, b) }
+func (ratArith) Quo(a, b *big.Rat) *big.Rat { return new(big.Rat).Quo(a, b) }
+func (ratArith) Equal(a, b *big.Rat) bool   { return a.Cmp(b) == 0 }
+func (ratArith) Valid(r *big.Rat) bool      { return r != nil && r.Sign() != 0 }
+
+// ConflictError reports an assertion that contradicts the ratio implied by
+// earlier assertions.
+type ConflictError[T comparable, R any] struct {
+	A, B              T
+	Asserted, Implied R
+}
+
+func (e *ConflictError[T, R]) Error() string {
+	return fmt.Sprintf("ratio: 1 %v = %v %v contradicts implied 1 %v = %v %v",
+		e.A, e.Asserted, e.B, e.A, e.Implied, e.B)
+}
+
+// Engine holds asserted ratios between elements of type T.
+type Engine[T comparable, R any] struct {
+	arith Arith[R]
+
+	// parent maps every element to its parent; if the parent of x is x,
+	// then x is the root of its set. factor maps every element other than
+	// a root to its ratio to its parent: 1 x = factor[x] parent[x].
+	parent map[T]T
+	factor map[T]R
+
+	// rank maps every root with a nonzero rank to it.
+	rank map[T]int
+}
+
+// New creates an empty Engine with the given arithmetic.
+func New[T comparable, R any](arith Arith[R]) *Engine[T, R] {
+	return &Engine[T, R]{
+		arith:  arith,
+		parent: make(map[T]T),
+		factor: make(map[T]R),
+		rank:   make(map[T]int),
+	}
+}
+
+// NewFloat creates an empty Engine with Float(tol) arithmetic.
+func NewFloat[T comparable](tol float64) *Engine[T, float64] {
+	return New[T](Float(tol))
+}
+
+// NewRat creates an empty Engine with Rat arithmetic.
+func NewRat[T comparable]() *Engine[T, *big.Rat] {
+	return New[T](Rat())
+}
+
+// find returns the root of the set of x and the ratio of x to it, adding x
+// as a singleton set if it is not present.
+func (e *Engine[T, R]) find(x T) (T, R) {
+	if _, ok := e.parent[x]; !ok {
+		e.parent[x] = x
+		return x, e.arith.One()
+	}
+
+	// find root and the product of the path
+	root, total := x, e.arith.One()
+	for p := e.parent[root]; p != root; p = e.parent[root] {
+		total = e.arith.Mul(total, e.factor[root])
+		root = p
+	}
+
+	// path compression
+	acc := total
+	for x != root {
+		p, f := e.parent[x], e.factor[x]
+		e.parent[x], e.factor[x] = root, acc
+		acc = e.arith.Quo(acc, f)
+		x = p
+	}
+	return root, total
+}
+
+// Assert asserts that 1 a = ratio b. If that contradicts the ratio implied
+// by earlier assertions, Assert returns a *ConflictError and ignores it.
+// Panics if ratio is not valid for the arithmetic.
+func (e *Engine[T, R]) Assert(a, b T, ratio R) error {
+	if !e.arith.Valid(ratio) {
+		panic("ratio.Engine: invalid ratio in Assert")
+	}
+	rootA, fa := e.find(a)
+	rootB, fb := e.find(b)
+	if rootA == rootB {
+		if implied := e.arith.Quo(fa, fb); !e.arith.Equal(implied, ratio) {
+			return &ConflictError[T, R]{A: a, B: b, Asserted: ratio, Implied: implied}
+		}
+		return nil
+	}
+
+	// 1 a = fa rootA and 1 b = fb rootB, so 1 rootB = fa / (ratio fb) rootA
+	link := e.arith.Quo(fa, e.arith.Mul(ratio, fb))
+	if e.rank[rootA] < e.rank[rootB] {
+		rootA, rootB = rootB, rootA
+		link = e.arith.Quo(e.arith.One(), link)
+	}
+	e.parent[rootB], e.factor[rootB] = rootA, link
+	if e.rank[rootA] == e.rank[rootB] {
+		e.rank[rootA]++
+	}
+	delete(e.rank, rootB)
+	return nil
+}
+
+// Ratio returns r and true if the assertions imply 1 a = r b, and false if
+// a and b are not related.
+// If a or b did not already exist, then singleton sets are created for them.
+func (e *Engine[T, R]) Ratio(a, b T) (R, bool) {
+	rootA, fa := e.find(a)
+	rootB, fb := e.find(b)
+	if rootA != rootB {
+		var zero R
+		return zero, false
+	}
+	return e.arith.Quo(fa, fb), true
+}
+
+// Connected reports whether a and b are related by the assertions.
+// If a or b did not already exist, then singleton sets are created for them.
+func (e *Engine[T, R]) Connected(a, b T) bool {
+	rootA, _ := e.find(a)
+	rootB, _ := e.find(b)
+	return rootA == rootB
+}
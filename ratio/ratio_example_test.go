@@ -0,0 +1,27 @@
+package ratio
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Example derives conversion factors between packaging units exactly and
+// reports an inconsistent fact.
+func Example() {
+	e := NewRat[string]()
+	e.Assert("pallet", "case", big.NewRat(40, 1))
+	e.Assert("case", "box", big.NewRat(6, 1))
+	e.Assert("box", "unit", big.NewRat(12, 1))
+
+	r, _ := e.Ratio("pallet", "unit")
+	fmt.Println("1 pallet =", r.RatString(), "units")
+	r, _ = e.Ratio("unit", "case")
+	fmt.Println("1 unit =", r.RatString(), "cases")
+
+	fmt.Println(e.Assert("case", "unit", big.NewRat(70, 1)))
+
+	// Output:
+	// 1 pallet = 2880 units
+	// 1 unit = 1/72 cases
+	// ratio: 1 case = 70/1 unit contradicts implied 1 case = 72/1 unit
+}
@@ -0,0 +1,108 @@
+package ratio
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// TestRatMatchesPotentials asserts ratios derived from hidden potentials
+// in random order and checks that every implied ratio is exact, and that
+// every perturbed assertion within a set is a conflict.
+func TestRatMatchesPotentials(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 60
+	for trial := 0; trial < 20; trial++ {
+		pot := make([]*big.Rat, n)
+		for i := range pot {
+			pot[i] = big.NewRat(int64(1+rng.Intn(50)), int64(1+rng.Intn(50)))
+		}
+		want := func(a, b int) *big.Rat { return new(big.Rat).Quo(pot[a], pot[b]) }
+
+		e := NewRat[int]()
+		ref := make([]int, n) // component labels
+		for i := range ref {
+			ref[i] = i
+		}
+		relabel := func(from, to int) {
+			for i := range ref {
+				if ref[i] == from {
+					ref[i] = to
+				}
+			}
+		}
+		for step := 0; step < 2*n; step++ {
+			a, b := rng.Intn(n), rng.Intn(n)
+			if ref[a] == ref[b] && rng.Intn(2) == 0 {
+				wrong := new(big.Rat).Mul(want(a, b), big.NewRat(2, 1))
+				var ce *ConflictError[int, *big.Rat]
+				if err := e.Assert(a, b, wrong); !errors.As(err, &ce) || ce.Implied.Cmp(want(a, b)) != 0 {
+					t.Fatalf("trial %d: Assert(%d, %d, %v) = %v", trial, a, b, wrong, err)
+				}
+				continue
+			}
+			if err := e.Assert(a, b, want(a, b)); err != nil {
+				t.Fatalf("trial %d: %v", trial, err)
+			}
+			relabel(ref[b], ref[a])
+
+			x, y := rng.Intn(n), rng.Intn(n)
+			r, ok := e.Ratio(x, y)
+			if ok != (ref[x] == ref[y]) || (ok && r.Cmp(want(x, y)) != 0) {
+				t.Fatalf("trial %d: Ratio(%d, %d) = %v, %v; want %v", trial, x, y, r, ok, want(x, y))
+			}
+			if e.Connected(x, y) != ok {
+				t.Fatalf("trial %d: Connected(%d, %d) = %v", trial, x, y, !ok)
+			}
+		}
+	}
+}
+
+func TestFloatTolerance(t *testing.T) {
+	e := NewFloat[string](1e-9)
+	e.Assert("USD", "EUR", 0.92)
+	e.Assert("EUR", "JPY", 163.5)
+	if err := e.Assert("USD", "JPY", 0.92*163.5*(1+1e-12)); err != nil {
+		t.Fatalf("rounding difference reported as conflict: %v", err)
+	}
+	if err := e.Assert("JPY", "USD", 1/150.0); err == nil {
+		t.Fatal("conflict not detected")
+	}
+	r, ok := e.Ratio("JPY", "USD")
+	if !ok || math.Abs(r-1/(0.92*163.5)) > 1e-12 {
+		t.Fatalf("Ratio(JPY, USD) = %v, %v", r, ok)
+	}
+	if _, ok := e.Ratio("USD", "GBP"); ok {
+		t.Fatal("unrelated units have a ratio")
+	}
+	if r, ok := e.Ratio("GBP", "GBP"); !ok || r != 1 {
+		t.Fatalf("Ratio(GBP, GBP) = %v, %v", r, ok)
+	}
+}
+
+func TestFloatLongChain(t *testing.T) {
+	e := NewFloat[int](1e-6)
+	const n = 100_000
+	for i := 1; i < n; i++ {
+		e.Assert(i, i-1, 1.00001)
+	}
+	r, _ := e.Ratio(n-1, 0)
+	if want := math.Pow(1.00001, n-1); math.Abs(r-want) > 1e-6*want {
+		t.Fatalf("Ratio(%d, 0) = %v, want %v", n-1, r, want)
+	}
+}
+
+func TestAssertPanicsOnInvalidRatio(t *testing.T) {
+	for _, r := range []float64{0, math.Inf(1), math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Assert with ratio %v did not panic", r)
+				}
+			}()
+			NewFloat[string](0).Assert("a", "b", r)
+		}()
+	}
+}
@@ -0,0 +1,101 @@
+package refine
+
+import (
+	"iter"
+	"slices"
+)
+
+// Generic is a refinable partition of a fixed set of comparable elements.
+// It numbers the elements in the order given to NewGeneric and refines a
+// Partition of the numbers.
+type Generic[T comparable] struct {
+	p     *Partition
+	elems []T
+	index map[T]int
+}
+
+// NewGeneric creates a partition of elems into a single class. Duplicates
+// are ignored.
+func NewGeneric[T comparable](elems ...T) *Generic[T] {
+	g := &Generic[T]{index: make(map[T]int, len(elems))}
+	for _, x := range elems {
+		if _, ok := g.index[x]; !ok {
+			g.index[x] = len(g.elems)
+			g.elems = append(g.elems, x)
+		}
+	}
+	g.p = New(len(g.elems))
+	return g
+}
+
+// Len returns the number of classes.
+func (g *Generic[T]) Len() int {
+	return g.p.Len()
+}
+
+// Class returns the class of x and true, or false if x is not an element
+// of the partition.
+func (g *Generic[T]) Class(x T) (int, bool) {
+	i, ok := g.index[x]
+	if !ok {
+		return 0, false
+	}
+	return g.p.class[i], true
+}
+
+// Size returns the number of elements of the class c.
+func (g *Generic[T]) Size(c int) int {
+	return g.p.Size(c)
+}
+
+// Members returns the elements of the class c, in no particular order.
+func (g *Generic[T]) Members(c int) []T {
+	members := make([]T, 0, g.p.Size(c))
+	for _, i := range g.p.elems[g.p.first[c]:g.p.end[c]] {
+		members = append(members, g.elems[i])
+	}
+	return members
+}
+
+// Refine splits every class that contains some but not all elements of
+// pivot, like Partition.Refine. Elements of pivot that are not elements
+// of the partition are ignored.
+func (g *Generic[T]) Refine(pivot []T) []Split {
+	indices := make([]int, 0, len(pivot))
+	for _, x := range pivot {
+		if i, ok := g.index[x]; ok {
+			indices = append(indices, i)
+		}
+	}
+	return g.p.Refine(indices)
+}
+
+// Classes returns an iterator over the classes and their elements, in
+// order of class.
+func (g *Generic[T]) Classes() iter.Seq2[int, []T] {
+	return func(yield func(int, []T) bool) {
+		for c := range g.p.first {
+			if !yield(c, g.Members(c)) {
+				return
+			}
+		}
+	}
+}
+
+// Groups returns a map from representative -> slice of elements in that
+// class, in the shape returned by gdsu.DSU.Groups. The representative of a
+// class is its element given first to NewGeneric, and the elements are in
+// the order given to NewGeneric.
+func (g *Generic[T]) Groups() map[T][]T {
+	groups := make(map[T][]T, g.p.Len())
+	for c := range g.p.first {
+		indices := slices.Clone(g.p.elems[g.p.first[c]:g.p.end[c]])
+		slices.Sort(indices)
+		members := make([]T, len(indices))
+		for k, i := range indices {
+			members[k] = g.elems[i]
+		}
+		groups[members[0]] = members
+	}
+	return groups
+}
@@ -0,0 +1,44 @@
+package refine
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestGeneric(t *testing.T) {
+	g := NewGeneric("a", "b", "c", "d", "b")
+	if g.Len() != 1 || g.Size(0) != 4 {
+		t.Fatalf("got %d classes with %d elements", g.Len(), g.Size(0))
+	}
+	splits := g.Refine([]string{"c", "a", "zzz"})
+	if len(splits) != 1 || splits[0] != (Split{Old: 0, New: 1}) {
+		t.Fatalf("got %v", splits)
+	}
+	if c, ok := g.Class("a"); !ok || c != 1 {
+		t.Fatalf("Class(a) = %d, %v", c, ok)
+	}
+	if _, ok := g.Class("zzz"); ok {
+		t.Fatal("unknown element has a class")
+	}
+	members := g.Members(1)
+	slices.Sort(members)
+	if !slices.Equal(members, []string{"a", "c"}) {
+		t.Fatalf("Members(1) = %v", members)
+	}
+
+	want := map[string][]string{"a": {"a", "c"}, "b": {"b", "d"}}
+	if got := g.Groups(); !maps.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	n := 0
+	for c, members := range g.Classes() {
+		if len(members) != g.Size(c) {
+			t.Fatalf("class %d has %d members", c, len(members))
+		}
+		n++
+	}
+	if n != 2 {
+		t.Fatalf("iterated %d classes", n)
+	}
+}
@@ -0,0 +1,158 @@
+// Package refine provides partition refinement, the counterpart of a DSU:
+// where a DSU only ever merges sets, a refinable partition only ever splits
+// them, by a pivot set that every class is split along.
+//
+// The elements are stored in one array, grouped by class, so that every
+// class is a contiguous range. Refine moves the pivot elements of every
+// class to the front of its range, and then splits off the moved part of
+// every class that the pivot touched but did not cover, in time linear in
+// the size of the pivot (Paige and Tarjan, 1987; Valmari and Lehtinen,
+// 2008). This is the step at the core of Hopcroft's DFA minimization,
+// bisimulation and color refinement of graphs.
+package refine
+
+import (
+	"iter"
+	"slices"
+)
+
+// Split reports that Refine split the class Old: the elements of Old in the
+// pivot moved to the new class New, and the others stayed in Old.
+type Split struct {
+	Old, New int
+}
+
+// Partition is a refinable partition of the elements [0, n). Classes are
+// numbered [0, Len()) in order of creation.
+type Partition struct {
+	// elems holds the elements grouped by class, and pos[x] is the index of
+	// x in elems; class[x] is the class of x.
+	elems []int
+	pos   []int
+	class []int
+
+	// class c occupies elems[first[c]:end[c]]; during Refine, the pivot
+	// elements of c are elems[first[c]:mid[c]].
+	first, mid, end []int
+}
+
+// New creates a partition of [0, size) into a single class, or into no
+// classes if size is 0.
+func New(size int) *Partition {
+	if size < 0 {
+		size = 0
+	}
+	p := &Partition{
+		elems: make([]int, size),
+		pos:   make([]int, size),
+		class: make([]int, size),
+	}
+	for x := range p.elems {
+		p.elems[x] = x
+		p.pos[x] = x
+	}
+	if size > 0 {
+		p.first, p.mid, p.end = []int{0}, []int{0}, []int{size}
+	}
+	return p
+}
+
+// check panics if x is out of range; method names the caller.
+func (p *Partition) check(x int, method string) {
+	if x < 0 || x >= len(p.elems) {
+		panic("refine.Partition: element out of range in " + method)
+	}
+}
+
+// Len returns the number of classes.
+func (p *Partition) Len() int {
+	return len(p.first)
+}
+
+// Class returns the class of x.
+// Panics if x is out of range.
+func (p *Partition) Class(x int) int {
+	p.check(x, "Class")
+	return p.class[x]
+}
+
+// Size returns the number of elements of the class c.
+func (p *Partition) Size(c int) int {
+	return p.end[c] - p.first[c]
+}
+
+// Members returns the elements of the class c, in no particular order.
+func (p *Partition) Members(c int) []int {
+	return slices.Clone(p.elems[p.first[c]:p.end[c]])
+}
+
+// Refine splits every class that contains some but not all elements of
+// pivot into the elements in pivot, which form a new class, and the others.
+// It returns the splits in order of the new classes. Duplicates in pivot
+// are ignored.
+// Panics if an element of pivot is out of range.
+func (p *Partition) Refine(pivot []int) []Split {
+	var touched []int
+	for _, x := range pivot {
+		p.check(x, "Refine")
+		c := p.class[x]
+		i := p.pos[x]
+		if i < p.mid[c] {
+			continue // already moved
+		}
+		if p.mid[c] == p.first[c] {
+			touched = append(touched, c)
+		}
+
+		// swap x to the end of the moved part
+		j := p.mid[c]
+		y := p.elems[j]
+		p.elems[i], p.elems[j] = y, x
+		p.pos[y], p.pos[x] = i, j
+		p.mid[c]++
+	}
+
+	var splits []Split
+	for _, c := range touched {
+		if p.mid[c] == p.end[c] {
+			// the pivot covers c
+			p.mid[c] = p.first[c]
+			continue
+		}
+		n := len(p.first)
+		p.first = append(p.first, p.first[c])
+		p.mid = append(p.mid, p.first[c])
+		p.end = append(p.end, p.mid[c])
+		for _, x := range p.elems[p.first[c]:p.mid[c]] {
+			p.class[x] = n
+		}
+		p.first[c] = p.mid[c]
+		splits = append(splits, Split{Old: c, New: n})
+	}
+	return splits
+}
+
+// Classes returns an iterator over the classes and their elements, in
+// order of class.
+func (p *Partition) Classes() iter.Seq2[int, []int] {
+	return func(yield func(int, []int) bool) {
+		for c := range p.first {
+			if !yield(c, p.Members(c)) {
+				return
+			}
+		}
+	}
+}
+
+// Groups returns a map from representative -> slice of elements in that
+// class, in the shape returned by gdsu.DSU.Groups. The representative of a
+// class is its smallest element, and the elements are sorted.
+func (p *Partition) Groups() map[int][]int {
+	groups := make(map[int][]int, len(p.first))
+	for c := range p.first {
+		members := p.Members(c)
+		slices.Sort(members)
+		groups[members[0]] = members
+	}
+	return groups
+}
@@ -0,0 +1,24 @@
+package refine
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// BenchmarkRefine refines a partition of 2^16 elements by 64 random
+// pivots of an eighth of the elements each.
+func BenchmarkRefine(b *testing.B) {
+	const n = 1 << 16
+	rng := rand.New(rand.NewSource(1))
+	pivots := make([][]int, 64)
+	for i := range pivots {
+		pivots[i] = rng.Perm(n)[:n/8]
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := New(n)
+		for _, pivot := range pivots {
+			p.Refine(pivot)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package refine
+
+import "fmt"
+
+// Example splits the numbers 0 to 9 by two pivots: the even numbers and
+// the multiples of 3.
+func Example() {
+	p := New(10)
+	fmt.Println(p.Refine([]int{0, 2, 4, 6, 8}))
+	fmt.Println(p.Refine([]int{0, 3, 6, 9}))
+	fmt.Println(p.Groups())
+
+	// Output:
+	// [{0 1}]
+	// [{1 2} {0 3}]
+	// map[0:[0 6] 1:[1 5 7] 2:[2 4 8] 3:[3 9]]
+}
+
+// ExampleGeneric_Refine computes the classes of the states of a DFA that
+// are distinguished by acceptance and by the class of their successor.
+func ExampleGeneric_Refine() {
+	next := map[string]string{"s": "t", "t": "u", "u": "u", "v": "u"}
+	accept := []string{"u", "v"}
+
+	g := NewGeneric("s", "t", "u", "v")
+	g.Refine(accept)
+	for changed := true; changed; {
+		changed = false
+		for c := 0; c < g.Len(); c++ {
+			// split every class by the states moving into c
+			members := g.Members(c)
+			var pre []string
+			for q, to := range next {
+				for _, m := range members {
+					if to == m {
+						pre = append(pre, q)
+					}
+				}
+			}
+			if len(g.Refine(pre)) > 0 {
+				changed = true
+			}
+		}
+	}
+	fmt.Println(g.Groups())
+
+	// Output:
+	// map[s:[s] t:[t] u:[u v]]
+}
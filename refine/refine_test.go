@@ -0,0 +1,113 @@
+package refine
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// TestRefineMatchesSignatures refines by random pivots and compares the
+// classes with the naive partition by membership in every pivot so far.
+func TestRefineMatchesSignatures(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		n := 1 + rng.Intn(60)
+		p := New(n)
+		sig := make([]string, n)
+		for step := 0; step < 8; step++ {
+			var pivot []int
+			in := make([]bool, n)
+			for k := rng.Intn(n + 1); k > 0; k-- {
+				x := rng.Intn(n)
+				pivot = append(pivot, x)
+				in[x] = true
+			}
+			before := p.Len()
+			oldClass := make([]int, n)
+			for x := range oldClass {
+				oldClass[x] = p.Class(x)
+			}
+
+			splits := p.Refine(pivot)
+			if p.Len() != before+len(splits) {
+				t.Fatalf("trial %d: %d classes after %d splits of %d", trial, p.Len(), len(splits), before)
+			}
+			for i, s := range splits {
+				if s.New != before+i {
+					t.Fatalf("trial %d: split %v out of order", trial, s)
+				}
+			}
+			for x := range sig {
+				sig[x] += fmt.Sprint(in[x])
+				if c := p.Class(x); c != oldClass[x] && !slices.Contains(splits, Split{Old: oldClass[x], New: c}) {
+					t.Fatalf("trial %d: %d moved to %d without a split", trial, x, c)
+				}
+			}
+
+			// x and y share a class iff they have the same signature
+			for x := 0; x < n; x++ {
+				for y := 0; y < n; y++ {
+					if (p.Class(x) == p.Class(y)) != (sig[x] == sig[y]) {
+						t.Fatalf("trial %d: classes of %d and %d disagree with signatures", trial, x, y)
+					}
+				}
+			}
+		}
+
+		total := 0
+		for c, members := range p.Classes() {
+			if len(members) != p.Size(c) {
+				t.Fatalf("trial %d: class %d has %d members, size %d", trial, c, len(members), p.Size(c))
+			}
+			for _, x := range members {
+				if p.Class(x) != c {
+					t.Fatalf("trial %d: %d listed in class %d", trial, x, c)
+				}
+			}
+			total += len(members)
+		}
+		if total != n {
+			t.Fatalf("trial %d: classes cover %d of %d elements", trial, total, n)
+		}
+	}
+}
+
+func TestGroupsShape(t *testing.T) {
+	p := New(6)
+	p.Refine([]int{4, 1, 4})
+	p.Refine([]int{5})
+	want := map[int][]int{0: {0, 2, 3}, 1: {1, 4}, 5: {5}}
+	if got := p.Groups(); !maps.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestRefineEdgeCases(t *testing.T) {
+	p := New(0)
+	if p.Len() != 0 || len(p.Refine(nil)) != 0 || len(p.Groups()) != 0 {
+		t.Fatal("empty partition is not empty")
+	}
+
+	// a pivot covering a class leaves it alone
+	p = New(3)
+	if splits := p.Refine([]int{2, 0, 1}); len(splits) != 0 {
+		t.Fatalf("got %v", splits)
+	}
+	if splits := p.Refine([]int{2, 0, 1, 1}); len(splits) != 0 {
+		t.Fatalf("got %v", splits)
+	}
+	if splits := p.Refine([]int{1}); len(splits) != 1 || p.Class(1) != 1 || p.Class(0) != 0 {
+		t.Fatalf("got %v", splits)
+	}
+}
+
+func TestRefinePanicsOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Refine did not panic")
+		}
+	}()
+	New(3).Refine([]int{3})
+}
@@ -0,0 +1,243 @@
+// Package scc maintains the strongly connected components of a directed
+// graph as edges are added one at a time.
+//
+// Every component is a set of a sparse.DSU, so nodes may be of any
+// comparable type. The condensation of the graph, which has one node per
+// component, is kept in topological order with the algorithm of Pearce and
+// Kelly (2006). An edge that respects the order is just recorded. An edge
+// that points backwards in the order triggers a search restricted to the
+// components between its endpoints: the components reachable from its head
+// and reaching its tail are reordered, and those that are both, which form
+// a new cycle with the edge, are contracted into a single component.
+package scc
+
+import (
+	"slices"
+
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// Graph is a directed graph with incrementally maintained strongly
+// connected components.
+type Graph[T comparable] struct {
+	// dsu groups the nodes into components; the root of every set is the
+	// representative of its component.
+	dsu *sparse.DSU[T]
+
+	// members maps every representative to the nodes of its component.
+	members map[T][]T
+
+	// ord maps every representative to the position of its component in
+	// the topological order of the condensation. Positions are distinct
+	// but not necessarily contiguous.
+	ord  map[T]int
+	next int
+
+	// out and in map every representative to the components it has edges
+	// to and from. Entries may name former representatives of components
+	// that have since been contracted; they are resolved with Find.
+	out map[T]map[T]struct{}
+	in  map[T]map[T]struct{}
+}
+
+// New creates an empty graph.
+func New[T comparable]() *Graph[T] {
+	return &Graph[T]{
+		dsu:     sparse.New[T](),
+		members: make(map[T][]T),
+		ord:     make(map[T]int),
+		out:     make(map[T]map[T]struct{}),
+		in:      make(map[T]map[T]struct{}),
+	}
+}
+
+// AddNode adds x as a component of its own if it is not already present.
+func (g *Graph[T]) AddNode(x T) {
+	g.find(x)
+}
+
+// find returns the representative of the component of x, adding x as a
+// new component placed last in the order if it is new.
+func (g *Graph[T]) find(x T) T {
+	r := g.dsu.Find(x)
+	if _, ok := g.ord[r]; !ok {
+		g.members[r] = []T{r}
+		g.ord[r] = g.next
+		g.next++
+	}
+	return r
+}
+
+// AddEdge adds the edge from u to v, adding the nodes if they are new.
+//
+// If the edge closes one or more cycles, the components on them are
+// contracted and AddEdge returns all nodes of the resulting component;
+// otherwise it returns nil.
+func (g *Graph[T]) AddEdge(u, v T) []T {
+	cu, cv := g.find(u), g.find(v)
+	if cu == cv {
+		return nil
+	}
+	link(g.out, cu, cv)
+	link(g.in, cv, cu)
+
+	lo, hi := g.ord[cv], g.ord[cu]
+	if hi < lo {
+		return nil
+	}
+
+	// forward: reachable from cv; backward: reaching cu; both are limited
+	// to the components between cv and cu in the current order
+	forward := g.search(cv, g.out, func(c T) bool { return g.ord[c] <= hi })
+	backward := g.search(cu, g.in, func(c T) bool { return g.ord[c] >= lo })
+
+	// pool the positions of all visited components and hand them out
+	// again: first to the backward-only components, then to the new
+	// cycle, if any, then to the forward-only components; positions left
+	// over by the contraction stay unused
+	var pool, before, cycle, after []T
+	for c := range backward {
+		pool = append(pool, c)
+		if _, ok := forward[c]; ok {
+			cycle = append(cycle, c)
+		} else {
+			before = append(before, c)
+		}
+	}
+	for c := range forward {
+		if _, ok := backward[c]; !ok {
+			pool = append(pool, c)
+			after = append(after, c)
+		}
+	}
+	byOrd := func(a, b T) int { return g.ord[a] - g.ord[b] }
+	slices.SortFunc(before, byOrd)
+	slices.SortFunc(cycle, byOrd)
+	slices.SortFunc(after, byOrd)
+	slots := make([]int, len(pool))
+	for i, c := range pool {
+		slots[i] = g.ord[c]
+	}
+	slices.Sort(slots)
+
+	// the backward-only components must take the lowest positions and the
+	// forward-only ones the highest; the cycle may take any in between
+	for i, c := range before {
+		g.ord[c] = slots[i]
+	}
+	for i, c := range after {
+		g.ord[c] = slots[len(slots)-len(after)+i]
+	}
+	if len(cycle) == 0 {
+		return nil
+	}
+	r := g.contract(cycle)
+	g.ord[r] = slots[len(before)]
+	return slices.Clone(g.members[r])
+}
+
+// link records an edge from the component a to the component b in adj.
+func link[T comparable](adj map[T]map[T]struct{}, a, b T) {
+	if adj[a] == nil {
+		adj[a] = make(map[T]struct{})
+	}
+	adj[a][b] = struct{}{}
+}
+
+// search returns the components reachable from start through adj while
+// staying within the components accepted by keep.
+func (g *Graph[T]) search(start T, adj map[T]map[T]struct{}, keep func(T) bool) map[T]struct{} {
+	visited := map[T]struct{}{start: {}}
+	stack := []T{start}
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for d := range adj[c] {
+			d = g.dsu.Find(d)
+			if _, ok := visited[d]; !ok && keep(d) {
+				visited[d] = struct{}{}
+				stack = append(stack, d)
+			}
+		}
+	}
+	return visited
+}
+
+// contract merges the given components into one and returns its
+// representative.
+func (g *Graph[T]) contract(cycle []T) T {
+	g.dsu.UnionAll(cycle...)
+	r := g.dsu.Find(cycle[0])
+	for _, c := range cycle {
+		if c == r {
+			continue
+		}
+		if len(g.members[r]) < len(g.members[c]) {
+			g.members[r], g.members[c] = g.members[c], g.members[r]
+		}
+		g.members[r] = append(g.members[r], g.members[c]...)
+		g.out[r] = mergeInto(g.out[r], g.out[c])
+		g.in[r] = mergeInto(g.in[r], g.in[c])
+		delete(g.members, c)
+		delete(g.ord, c)
+		delete(g.out, c)
+		delete(g.in, c)
+	}
+	// drop the edges inside the new component
+	for _, adj := range []map[T]map[T]struct{}{g.out, g.in} {
+		for d := range adj[r] {
+			if g.dsu.Find(d) == r {
+				delete(adj[r], d)
+			}
+		}
+	}
+	return r
+}
+
+// mergeInto adds the entries of src to dst, reusing the larger of the two.
+func mergeInto[T comparable](dst, src map[T]struct{}) map[T]struct{} {
+	if len(dst) < len(src) {
+		dst, src = src, dst
+	}
+	if dst == nil {
+		return nil
+	}
+	for x := range src {
+		dst[x] = struct{}{}
+	}
+	return dst
+}
+
+// Component returns the representative of the strongly connected
+// component containing x, adding x if it is new.
+func (g *Graph[T]) Component(x T) T {
+	return g.find(x)
+}
+
+// SameComponent reports whether x and y are in the same strongly connected
+// component, adding them if they are new.
+func (g *Graph[T]) SameComponent(x, y T) bool {
+	return g.find(x) == g.find(y)
+}
+
+// Members returns the nodes of the strongly connected component containing
+// x, adding x if it is new. The caller must not modify the result.
+func (g *Graph[T]) Members(x T) []T {
+	return g.members[g.find(x)]
+}
+
+// Components returns the strongly connected components in topological
+// order: if there is an edge from a node of one component to a node of
+// another, the first component comes earlier.
+func (g *Graph[T]) Components() [][]T {
+	reps := make([]T, 0, len(g.ord))
+	for r := range g.ord {
+		reps = append(reps, r)
+	}
+	slices.SortFunc(reps, func(a, b T) int { return g.ord[a] - g.ord[b] })
+	comps := make([][]T, len(reps))
+	for i, r := range reps {
+		comps[i] = slices.Clone(g.members[r])
+	}
+	return comps
+}
@@ -0,0 +1,24 @@
+package scc
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Example reports the modules that collapse together when a new import
+// closes a cycle.
+func Example() {
+	g := New[string]()
+	g.AddEdge("app", "store")
+	g.AddEdge("store", "cache")
+	g.AddEdge("cache", "log")
+
+	merged := g.AddEdge("log", "store")
+	slices.Sort(merged)
+	fmt.Println(merged)
+	fmt.Println(len(g.Components()), g.SameComponent("app", "store"))
+
+	// Output:
+	// [cache log store]
+	// 2 false
+}
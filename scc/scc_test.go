@@ -0,0 +1,140 @@
+package scc
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// reachable returns reach[x][y] == true iff y is reachable from x.
+func reachable(n int, edges [][2]int) [][]bool {
+	adj := make([][]int, n)
+	for _, e := range edges {
+		adj[e[0]] = append(adj[e[0]], e[1])
+	}
+	reach := make([][]bool, n)
+	for x := range reach {
+		reach[x] = make([]bool, n)
+		reach[x][x] = true
+		stack := []int{x}
+		for len(stack) > 0 {
+			y := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, z := range adj[y] {
+				if !reach[x][z] {
+					reach[x][z] = true
+					stack = append(stack, z)
+				}
+			}
+		}
+	}
+	return reach
+}
+
+// TestGraphMatchesNaive adds random edges and checks the components, the
+// merges reported by AddEdge and the topological order after every edge.
+func TestGraphMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		n := 2 + rng.Intn(25)
+		g := New[int]()
+		for x := 0; x < n; x++ {
+			g.AddNode(x)
+		}
+		var edges [][2]int
+		for e := 0; e < 2*n; e++ {
+			u, v := rng.Intn(n), rng.Intn(n)
+			before := g.Members(u)
+			wasSame := g.SameComponent(u, v)
+			merged := g.AddEdge(u, v)
+			edges = append(edges, [2]int{u, v})
+			reach := reachable(n, edges)
+
+			for x := 0; x < n; x++ {
+				for y := 0; y < n; y++ {
+					if want := reach[x][y] && reach[y][x]; g.SameComponent(x, y) != want {
+						t.Fatalf("trial %d: SameComponent(%d, %d) = %v, want %v", trial, x, y, !want, want)
+					}
+				}
+			}
+
+			grew := len(g.Members(u)) > len(before)
+			if (merged != nil) != grew || (wasSame && merged != nil) {
+				t.Fatalf("trial %d: AddEdge(%d, %d) = %v, component grew %v", trial, u, v, merged, grew)
+			}
+			if merged != nil {
+				got := slices.Sorted(slices.Values(merged))
+				want := slices.Sorted(slices.Values(g.Members(v)))
+				if !slices.Equal(got, want) {
+					t.Fatalf("trial %d: AddEdge(%d, %d) = %v, want %v", trial, u, v, got, want)
+				}
+			}
+
+			position := make([]int, n)
+			for i, comp := range g.Components() {
+				for _, x := range comp {
+					position[x] = i
+				}
+			}
+			for _, e := range edges {
+				if position[e[0]] > position[e[1]] {
+					t.Fatalf("trial %d: edge %v goes backwards in the order", trial, e)
+				}
+			}
+		}
+	}
+}
+
+// TestGraphImportCycle detects a cycle created by a new import.
+func TestGraphImportCycle(t *testing.T) {
+	g := New[string]()
+	g.AddEdge("main", "http")
+	g.AddEdge("http", "net")
+	g.AddEdge("net", "io")
+	if merged := g.AddEdge("main", "io"); merged != nil {
+		t.Fatalf("expected no cycle, got %v", merged)
+	}
+
+	merged := g.AddEdge("io", "http")
+	slices.Sort(merged)
+	if !slices.Equal(merged, []string{"http", "io", "net"}) {
+		t.Fatalf("unexpected merge %v", merged)
+	}
+	if g.Component("net") != g.Component("http") || g.SameComponent("main", "io") {
+		t.Fatalf("unexpected components")
+	}
+	if comps := g.Components(); len(comps) != 2 || !slices.Equal(comps[0], []string{"main"}) {
+		t.Fatalf("unexpected order %v", comps)
+	}
+}
+
+// TestGraphSelfLoop ensures self loops and edges inside a component do not
+// report merges.
+func TestGraphSelfLoop(t *testing.T) {
+	g := New[int]()
+	if g.AddEdge(1, 1) != nil {
+		t.Fatalf("self loop reported a merge")
+	}
+	g.AddEdge(1, 2)
+	if g.AddEdge(2, 1) == nil {
+		t.Fatalf("expected a merge")
+	}
+	if g.AddEdge(2, 1) != nil || len(g.Members(1)) != 2 {
+		t.Fatalf("edge inside a component reported a merge")
+	}
+}
+
+// TestGraphLongCycle closes a long path into a single component.
+func TestGraphLongCycle(t *testing.T) {
+	const n = 100_000
+	g := New[int]()
+	for x := 1; x < n; x++ {
+		g.AddEdge(x-1, x)
+	}
+	if merged := g.AddEdge(n-1, 0); len(merged) != n {
+		t.Fatalf("expected %d merged nodes, got %d", n, len(merged))
+	}
+	if len(g.Components()) != 1 {
+		t.Fatalf("expected a single component")
+	}
+}
@@ -0,0 +1,119 @@
+package sparse
+
+import "fmt"
+
+// CannotLinkError reports that a union would put the elements of a
+// cannot-link constraint in the same set.
+type CannotLinkError[T comparable] struct {
+	// X and Y are the elements of the violated constraint, in the order
+	// given to CannotLink.
+	X, Y T
+}
+
+func (e *CannotLinkError[T]) Error() string {
+	return fmt.Sprintf("sparse: union violates cannot-link constraint between %v and %v", e.X, e.Y)
+}
+
+// Constrained is a DSU with cannot-link constraints: pairs of elements that
+// must never be in the same set. Unions that would violate a constraint
+// fail and leave the sets unchanged.
+//
+// Every set keeps the list of constraints with an element in it. A union
+// checks the shorter of the two lists for a constraint whose other element
+// is in the other set, and then appends it to the longer one, so every
+// constraint moves O(log n) times in total.
+type Constrained[T comparable] struct {
+	dsu *DSU[T]
+
+	// cannot maps the root of every set with constraints to them.
+	cannot map[T][][2]T
+}
+
+// NewConstrained creates an empty Constrained DSU.
+func NewConstrained[T comparable]() *Constrained[T] {
+	return &Constrained[T]{dsu: New[T](), cannot: make(map[T][][2]T)}
+}
+
+// Find returns the representative element (root) of the set containing x.
+// If x is not present, it is added as a singleton set.
+func (c *Constrained[T]) Find(x T) T {
+	return c.dsu.Find(x)
+}
+
+// CannotLink forbids x and y from ever being in the same set. It returns a
+// *CannotLinkError if they already are.
+// If x or y did not already exist, then singleton sets are created for them.
+func (c *Constrained[T]) CannotLink(x, y T) error {
+	rootX, rootY := c.dsu.Find(x), c.dsu.Find(y)
+	if rootX == rootY {
+		return &CannotLinkError[T]{X: x, Y: y}
+	}
+	c.cannot[rootX] = append(c.cannot[rootX], [2]T{x, y})
+	c.cannot[rootY] = append(c.cannot[rootY], [2]T{x, y})
+	return nil
+}
+
+// violation returns a constraint between the sets whose roots are rootX
+// and rootY, checking the shorter of their lists.
+func (c *Constrained[T]) violation(rootX, rootY T) ([2]T, bool) {
+	short, other := rootX, rootY
+	if len(c.cannot[short]) > len(c.cannot[other]) {
+		short, other = other, short
+	}
+	for _, p := range c.cannot[short] {
+		if c.dsu.Find(p[0]) == other || c.dsu.Find(p[1]) == other {
+			return p, true
+		}
+	}
+	return [2]T{}, false
+}
+
+// CanUnion reports whether x and y are in the same set or merging their
+// sets would violate no constraint.
+// If x or y did not already exist, then singleton sets are created for them.
+func (c *Constrained[T]) CanUnion(x, y T) bool {
+	rootX, rootY := c.dsu.Find(x), c.dsu.Find(y)
+	if rootX == rootY {
+		return true
+	}
+	_, violated := c.violation(rootX, rootY)
+	return !violated
+}
+
+// Union merges the sets containing x and y. Returns true if the sets were
+// separate and are now merged, and false if they were already the same
+// set. If merging would violate a constraint, Union returns a
+// *CannotLinkError naming it and leaves the sets unchanged.
+// If x or y did not already exist, then singleton sets are created for them.
+func (c *Constrained[T]) Union(x, y T) (bool, error) {
+	rootX, rootY := c.dsu.Find(x), c.dsu.Find(y)
+	if rootX == rootY {
+		return false, nil
+	}
+	if p, violated := c.violation(rootX, rootY); violated {
+		return false, &CannotLinkError[T]{X: p[0], Y: p[1]}
+	}
+
+	long, short := c.cannot[rootX], c.cannot[rootY]
+	if len(long) < len(short) {
+		long, short = short, long
+	}
+	delete(c.cannot, rootX)
+	delete(c.cannot, rootY)
+	c.dsu.Union(rootX, rootY)
+	if merged := append(long, short...); len(merged) > 0 {
+		c.cannot[c.dsu.Find(rootX)] = merged
+	}
+	return true, nil
+}
+
+// Connected reports whether x and y are in the same set.
+// If x or y did not already exist, then singleton sets are created for them.
+func (c *Constrained[T]) Connected(x, y T) bool {
+	return c.dsu.Connected(x, y)
+}
+
+// Groups returns a map from root -> slice of elements in that set.
+func (c *Constrained[T]) Groups() map[T][]T {
+	return c.dsu.Groups()
+}
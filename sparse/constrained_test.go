@@ -0,0 +1,98 @@
+package sparse
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// TestConstrainedMatchesBruteForce performs random constraints and unions
+// and compares them with a plain DSU that checks every constraint.
+func TestConstrainedMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 100
+	for trial := 0; trial < 20; trial++ {
+		c := NewConstrained[int]()
+		ref := New[int]()
+		var constraints [][2]int
+		for i := 0; i < 4*n; i++ {
+			x, y := rng.Intn(n), rng.Intn(n)
+			if rng.Intn(4) == 0 {
+				err := c.CannotLink(x, y)
+				if (err != nil) != ref.Connected(x, y) {
+					t.Fatalf("CannotLink(%d, %d) = %v", x, y, err)
+				}
+				if err == nil {
+					constraints = append(constraints, [2]int{x, y})
+				}
+				continue
+			}
+
+			violated := false
+			for _, p := range constraints {
+				a, b := ref.Find(p[0]), ref.Find(p[1])
+				rx, ry := ref.Find(x), ref.Find(y)
+				if (a == rx && b == ry) || (a == ry && b == rx) {
+					violated = true
+				}
+			}
+			if c.CanUnion(x, y) == violated {
+				t.Fatalf("CanUnion(%d, %d) = %v", x, y, !violated)
+			}
+			merged, err := c.Union(x, y)
+			var cle *CannotLinkError[int]
+			if violated {
+				if merged || !errors.As(err, &cle) {
+					t.Fatalf("Union(%d, %d) = %v, %v; want violation", x, y, merged, err)
+				}
+				a, b := ref.Find(cle.X), ref.Find(cle.Y)
+				rx, ry := ref.Find(x), ref.Find(y)
+				if !((a == rx && b == ry) || (a == ry && b == rx)) {
+					t.Fatalf("Union(%d, %d) names unrelated constraint %v", x, y, err)
+				}
+				continue
+			}
+			if err != nil || merged != ref.Union(x, y) {
+				t.Fatalf("Union(%d, %d) = %v, %v", x, y, merged, err)
+			}
+		}
+		for x := 0; x < n; x++ {
+			if c.Find(x) != c.Find(ref.Find(x)) || !c.Connected(x, ref.Find(x)) {
+				t.Fatalf("trial %d: sets differ at %d", trial, x)
+			}
+		}
+		for _, p := range constraints {
+			if c.Connected(p[0], p[1]) {
+				t.Fatalf("trial %d: constraint %v violated", trial, p)
+			}
+		}
+	}
+}
+
+// TestConstrainedErrors ensures invalid constraints and violating unions
+// are reported and leave the sets unchanged.
+func TestConstrainedErrors(t *testing.T) {
+	c := NewConstrained[string]()
+	if err := c.CannotLink("a", "a"); err == nil {
+		t.Fatal("CannotLink(a, a) succeeded")
+	}
+	if err := c.CannotLink("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	c.Union("a", "x")
+	c.Union("b", "y")
+	merged, err := c.Union("x", "y")
+	if merged || err == nil {
+		t.Fatalf("Union(x, y) = %v, %v", merged, err)
+	}
+	want := "sparse: union violates cannot-link constraint between a and b"
+	if err.Error() != want {
+		t.Fatalf("got %q, want %q", err, want)
+	}
+	if c.Connected("x", "y") || len(c.Groups()) != 2 {
+		t.Fatal("failed union changed the sets")
+	}
+	if merged, err := c.Union("a", "x"); merged || err != nil {
+		t.Fatalf("repeated Union = %v, %v", merged, err)
+	}
+}
@@ -8,57 +8,176 @@
 // ahead of time or cannot be restricted to integer ranges.
 package sparse
 
-import "github.com/arunksaha/gdsu"
+import (
+	"hash/maphash"
+	"maps"
+	"slices"
+
+	"github.com/arunksaha/gdsu"
+)
+
+// Storage is split into pages by the hash of the element so that Fork can
+// share unchanged pages between DSUs and copy only the pages that are
+// written to. The pages form an extendible hash table: a page of depth d
+// holds the elements whose hashes agree in their low d bits, and a page
+// that grows beyond maxPageLen elements is split in two by the next bit.
+const maxPageLen = 256
 
 // DSU is a sparse, map-backed generic implementation of a Disjoint-Set Union.
 //
 // It does NOT require a fixed capacity or pre-registration of elements.
 // Elements are added lazily when first seen by Find/Union.
 type DSU[T comparable] struct {
-	// parent stores the immediate parent of each element;
-	// if parent[x] == x, then x is the root of its set.
-	parent map[T]T
+	// pages is the directory of pages: x is stored in the page at index
+	// hash(x) & (len(pages)-1). A page of depth d appears at every index
+	// with the same low d bits, and first at the index below 1<<d.
+	pages []*page[T]
+
+	// seed seeds the hash; forks share it so that they place elements alike.
+	seed maphash.Seed
+
+	// owner marks the pages this DSU may write to in place. Pages with any
+	// other owner may be referenced by another DSU created through Fork,
+	// and are copied before they are written to.
+	owner *owner
+}
+
+// owner identifies the writer of pages. It is not zero-sized, so that
+// distinct owners have distinct addresses.
+type owner struct{ _ byte }
+
+// page holds the entries of the elements whose hashes share its low bits.
+type page[T comparable] struct {
+	entries map[T]entry[T]
 
-	// rank stores an upper bound on the height of the tree rooted at each element.
+	// depth is the number of low hash bits that the elements share.
+	depth int
+	owner *owner
+}
+
+// entry is the entry of an element.
+type entry[T comparable] struct {
+	// parent is the immediate parent of the element;
+	// if the parent of x is x, then x is the root of its set.
+	parent T
+
+	// rank is an upper bound on the height of the tree rooted at the element.
 	// Used with union-by-rank to keep trees shallow and operations near O(1).
-	rank map[T]int
+	rank int
 }
 
 // New creates a new DSU initialized with the given elements.
 // Additional elements may still be added later via Find/Union.
 func New[T comparable](elems ...T) *DSU[T] {
-	dsu := &DSU[T]{
-		parent: make(map[T]T, len(elems)),
-		rank:   make(map[T]int, len(elems)),
-	}
+	dsu := &DSU[T]{seed: maphash.MakeSeed(), owner: new(owner)}
+	dsu.pages = []*page[T]{{
+		entries: make(map[T]entry[T], min(len(elems), maxPageLen)),
+		owner:   dsu.owner,
+	}}
 	for _, e := range elems {
-		dsu.parent[e] = e
-		dsu.rank[e] = 0
+		dsu.add(e)
 	}
 	return dsu
 }
 
+// Fork returns an independent copy of the DSU in O(n / maxPageLen) time.
+//
+// The fork and the original share all pages until either of them writes
+// to a page, at which point the writer takes a private copy of just that
+// page. Both may then evolve separately; neither observes the other's
+// subsequent unions. Forks of forks are supported.
+func (dsu *DSU[T]) Fork() *DSU[T] {
+	dsu.owner = new(owner)
+	return &DSU[T]{pages: slices.Clone(dsu.pages), seed: dsu.seed, owner: new(owner)}
+}
+
+// index returns the directory index of x.
+func (dsu *DSU[T]) index(x T) int {
+	return int(maphash.Comparable(dsu.seed, x) & uint64(len(dsu.pages)-1))
+}
+
+// writable returns the page at index i, copying it first if it is not
+// owned by dsu.
+func (dsu *DSU[T]) writable(i int) *page[T] {
+	pg := dsu.pages[i]
+	if pg.owner == dsu.owner {
+		return pg
+	}
+	cp := &page[T]{entries: maps.Clone(pg.entries), depth: pg.depth, owner: dsu.owner}
+	for j := i & (1<<pg.depth - 1); j < len(dsu.pages); j += 1 << pg.depth {
+		dsu.pages[j] = cp
+	}
+	return cp
+}
+
+// lookup returns the entry of x and whether x is present.
+func (dsu *DSU[T]) lookup(x T) (entry[T], bool) {
+	e, ok := dsu.pages[dsu.index(x)].entries[x]
+	return e, ok
+}
+
+// store sets the entry of x to e, copying a shared page first.
+func (dsu *DSU[T]) store(x T, e entry[T]) {
+	dsu.writable(dsu.index(x)).entries[x] = e
+}
+
+// add adds x as a singleton set, splitting its page if it grows too large.
+func (dsu *DSU[T]) add(x T) {
+	i := dsu.index(x)
+	pg := dsu.writable(i)
+	pg.entries[x] = entry[T]{parent: x}
+	if len(pg.entries) > maxPageLen {
+		dsu.split(i)
+	}
+}
+
+// split splits the page at index i, which dsu owns, by bit depth of the
+// hash, doubling the directory first if the page appears only once. The
+// elements with the bit set move to a new page.
+func (dsu *DSU[T]) split(i int) {
+	pg := dsu.pages[i]
+	if 1<<pg.depth == len(dsu.pages) {
+		dsu.pages = append(dsu.pages, dsu.pages...)
+	}
+	bit := 1 << pg.depth
+	pg.depth++
+	hi := &page[T]{entries: make(map[T]entry[T], maxPageLen/2), depth: pg.depth, owner: dsu.owner}
+	for x, e := range pg.entries {
+		if maphash.Comparable(dsu.seed, x)&uint64(bit) != 0 {
+			hi.entries[x] = e
+			delete(pg.entries, x)
+		}
+	}
+	for j := i&(bit-1) | bit; j < len(dsu.pages); j += 2 * bit {
+		dsu.pages[j] = hi
+	}
+}
+
 // Find returns the representative element (root) of the set containing x.
 // If x is not present, it is added as a singleton set.
 func (dsu *DSU[T]) Find(x T) T {
 	// if unseen, initialize
-	if _, ok := dsu.parent[x]; !ok {
-		dsu.parent[x] = x
-		dsu.rank[x] = 0
+	e, ok := dsu.lookup(x)
+	if !ok {
+		dsu.add(x)
+		return x
+	}
+	if e.parent == x {
 		return x
 	}
 
 	// find root
-	root := x
-	for dsu.parent[root] != root {
-		root = dsu.parent[root]
+	root := e.parent
+	for r, _ := dsu.lookup(root); r.parent != root; r, _ = dsu.lookup(root) {
+		root = r.parent
 	}
 
 	// path compression
-	for x != root {
-		p := dsu.parent[x]
-		dsu.parent[x] = root
-		x = p
+	for e.parent != root {
+		next := e.parent
+		dsu.store(x, entry[T]{parent: root, rank: e.rank})
+		x = next
+		e, _ = dsu.lookup(x)
 	}
 
 	return root
@@ -71,12 +190,15 @@ func (dsu *DSU[T]) Union(x, y T) bool {
 	if rootX == rootY {
 		return false
 	}
-	if dsu.rank[rootX] < dsu.rank[rootY] {
+	ex, _ := dsu.lookup(rootX)
+	ey, _ := dsu.lookup(rootY)
+	if ex.rank < ey.rank {
 		rootX, rootY = rootY, rootX
+		ex, ey = ey, ex
 	}
-	dsu.parent[rootY] = rootX
-	if dsu.rank[rootX] == dsu.rank[rootY] {
-		dsu.rank[rootX]++
+	dsu.store(rootY, entry[T]{parent: rootX, rank: ey.rank})
+	if ex.rank == ey.rank {
+		dsu.store(rootX, entry[T]{parent: rootX, rank: ex.rank + 1})
 	}
 	return true
 }
@@ -87,15 +209,76 @@ func (dsu *DSU[T]) Connected(x, y T) bool {
 	return dsu.Find(x) == dsu.Find(y)
 }
 
+// UnionAll merges the sets containing all of xs into one set.
+// Returns the number of merges that occurred.
+func (dsu *DSU[T]) UnionAll(xs ...T) int {
+	merges := 0
+	for i := 1; i < len(xs); i++ {
+		if dsu.Union(xs[0], xs[i]) {
+			merges++
+		}
+	}
+	if len(xs) == 1 {
+		dsu.Find(xs[0])
+	}
+	return merges
+}
+
+// UnionPairs merges the sets containing each pair of elements.
+// Returns the number of merges that occurred.
+func (dsu *DSU[T]) UnionPairs(pairs [][2]T) int {
+	merges := 0
+	for _, p := range pairs {
+		if dsu.Union(p[0], p[1]) {
+			merges++
+		}
+	}
+	return merges
+}
+
+// FindMany appends the representative of each element of xs to dst and
+// returns the extended slice; the i-th appended value is the root of xs[i].
+// Elements not already present are added as singleton sets.
+func (dsu *DSU[T]) FindMany(dst, xs []T) []T {
+	dst = slices.Grow(dst, len(xs))
+	for _, x := range xs {
+		dst = append(dst, dsu.Find(x))
+	}
+	return dst
+}
+
+// ConnectedMany appends to dst whether the elements of each pair are in the
+// same set and returns the extended slice.
+// Elements not already present are added as singleton sets.
+func (dsu *DSU[T]) ConnectedMany(dst []bool, pairs [][2]T) []bool {
+	dst = slices.Grow(dst, len(pairs))
+	for _, p := range pairs {
+		dst = append(dst, dsu.Find(p[0]) == dsu.Find(p[1]))
+	}
+	return dst
+}
+
 // Groups returns a map from root -> slice of elements in that set.
 func (dsu *DSU[T]) Groups() map[T][]T {
 	groups := make(map[T][]T)
-	for x := range dsu.parent {
+	dsu.each(func(x T) {
 		root := dsu.Find(x)
 		groups[root] = append(groups[root], x)
-	}
+	})
 	return groups
 }
 
-// Compile-time assertion that DSU[int] implements gdsu.DSU[int].
-var _ gdsu.DSU[int] = (*DSU[int])(nil)
+// each calls fn once for every element. fn may call Find.
+func (dsu *DSU[T]) each(fn func(x T)) {
+	for i, pg := range dsu.pages {
+		// visit every page at its first index only
+		if i < 1<<pg.depth {
+			for x := range pg.entries {
+				fn(x)
+			}
+		}
+	}
+}
+
+// Compile-time assertion that DSU[int] implements gdsu.BatchDSU[int].
+var _ gdsu.BatchDSU[int] = (*DSU[int])(nil)
@@ -137,3 +137,21 @@ func BenchmarkSparseGroupsSmall(b *testing.B) {
 		_ = dsu.Groups()
 	}
 }
+
+// BenchmarkSparseFork measures forking a populated DSU and applying a
+// small speculative batch of unions to the fork.
+func BenchmarkSparseFork(b *testing.B) {
+	b.ReportAllocs()
+	dsu := New[int]()
+	for i := 0; i < NumElements; i += 2 {
+		dsu.Union(i, i+1)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		fork := dsu.Fork()
+		for i := 0; i < 50; i++ {
+			fork.Union(rand.Intn(NumElements), rand.Intn(NumElements))
+		}
+	}
+}
@@ -178,3 +178,59 @@ func ExampleDSU_Groups() {
 	// euler,fermat,gauss,ramanujan
 	// bose,einstein,gallileo,newton
 }
+
+// ExampleDSU_Fork illustrates evaluating a speculative scenario on a fork
+// without affecting the original.
+func ExampleDSU_Fork() {
+	dsu := New("alice", "bob", "carol")
+	dsu.Union("alice", "bob")
+
+	scenario := dsu.Fork()
+	scenario.Union("bob", "carol")
+
+	fmt.Println(scenario.Connected("alice", "carol")) // merged in the scenario
+	fmt.Println(dsu.Connected("alice", "carol"))      // original unchanged
+
+	// Output:
+	// true
+	// false
+}
+
+// ExampleWitness_Path illustrates explaining a connection with the labeled
+// unions that established it, and the cycle behind a redundant union.
+func ExampleWitness_Path() {
+	w := NewWitness[string, string]()
+	w.UnionWith("alice", "bob", "same team")
+	w.UnionWith("carol", "bob", "shared office")
+	w.UnionWith("carol", "dave", "same project")
+
+	path, _ := w.Path("alice", "dave")
+	for _, e := range path {
+		fmt.Println(e.X, e.Y, e.Label)
+	}
+
+	cycle, merged := w.UnionWith("dave", "alice", "same lunch")
+	fmt.Println(merged, len(cycle))
+
+	// Output:
+	// alice bob same team
+	// carol bob shared office
+	// carol dave same project
+	// false 3
+}
+
+// ExampleConstrained_Union illustrates a union refused by a cannot-link
+// constraint.
+func ExampleConstrained_Union() {
+	c := NewConstrained[string]()
+	c.CannotLink("alice", "bob")
+	c.Union("alice", "carol")
+
+	fmt.Println(c.CanUnion("carol", "dave"), c.CanUnion("carol", "bob"))
+	_, err := c.Union("bob", "carol")
+	fmt.Println(err)
+
+	// Output:
+	// true false
+	// sparse: union violates cannot-link constraint between alice and bob
+}
@@ -152,3 +152,199 @@ func TestSparseUnionRankSwapSparse(t *testing.T) {
 		t.Fatalf("expected a to remain the root due to higher rank, got %v", root)
 	}
 }
+
+// TestSparseForkIndependent ensures a fork and its original evolve separately.
+func TestSparseForkIndependent(t *testing.T) {
+	dsu := New("a", "b", "c", "d")
+	dsu.Union("a", "b")
+
+	fork := dsu.Fork()
+	if !fork.Connected("a", "b") {
+		t.Fatal("fork should inherit existing unions")
+	}
+
+	fork.Union("b", "x")
+	dsu.Union("c", "d")
+
+	if dsu.Connected("a", "x") {
+		t.Fatal("union in fork leaked into the original")
+	}
+	if fork.Connected("c", "d") {
+		t.Fatal("union in original leaked into the fork")
+	}
+	if !fork.Connected("a", "x") || !dsu.Connected("c", "d") {
+		t.Fatal("unions after fork were lost")
+	}
+	if len(dsu.Groups()) != 3 || len(fork.Groups()) != 3 {
+		t.Fatalf("unexpected group counts %d, %d", len(dsu.Groups()), len(fork.Groups()))
+	}
+}
+
+// privatePages returns the number of distinct pages that dsu may write to
+// in place.
+func privatePages[T comparable](dsu *DSU[T]) int {
+	n := 0
+	for i, pg := range dsu.pages {
+		if i < 1<<pg.depth && pg.owner == dsu.owner {
+			n++
+		}
+	}
+	return n
+}
+
+// TestSparseForkCopiesOnlyModified ensures a fork copies only the pages it
+// writes to.
+func TestSparseForkCopiesOnlyModified(t *testing.T) {
+	dsu := New[int]()
+	for i := 0; i < 100_000; i += 2 {
+		dsu.Union(i, i+1)
+	}
+
+	fork := dsu.Fork()
+	fork.Union(-1, -2)
+
+	if got := privatePages(fork); got < 1 || got > 2 {
+		t.Fatalf("expected the fork to copy 1 or 2 pages, got %d", got)
+	}
+	if got := privatePages(dsu); got != 0 {
+		t.Fatalf("expected the original to copy no pages, got %d", got)
+	}
+	if fork.Connected(0, 2) || !fork.Connected(0, 1) || dsu.Connected(-1, -2) {
+		t.Fatal("unexpected connectivity after fork")
+	}
+}
+
+// TestSparseForkEvolving ensures repeated forks of an original that keeps
+// changing see the state at their fork and leave the original's pages
+// alone.
+func TestSparseForkEvolving(t *testing.T) {
+	const n = 100_000
+	dsu := New[int]()
+	for i := 0; i < n; i++ {
+		dsu.Find(i)
+	}
+	pages := len(dsu.pages)
+
+	var forks []*DSU[int]
+	for i := 0; i < 50; i++ {
+		forks = append(forks, dsu.Fork())
+		if got := privatePages(forks[i]); got != 0 {
+			t.Fatalf("fork %d: expected no private pages, got %d", i, got)
+		}
+		dsu.Union(i, i+1)
+		if got := privatePages(dsu); got > 2 {
+			t.Fatalf("round %d: expected the original to copy at most 2 pages, got %d", i, got)
+		}
+	}
+
+	if len(dsu.pages) != pages {
+		t.Fatalf("expected %d pages, got %d", pages, len(dsu.pages))
+	}
+	for i, f := range forks {
+		if i > 0 && !f.Connected(0, i) {
+			t.Fatalf("fork %d: expected 0 and %d to be connected", i, i)
+		}
+		if f.Connected(0, i+1) {
+			t.Fatalf("fork %d: expected 0 and %d to be disconnected", i, i+1)
+		}
+	}
+	if !dsu.Connected(0, 50) || len(dsu.Groups()) != n-50 {
+		t.Fatal("unions on the original were lost")
+	}
+}
+
+// TestSparseForkGrowth ensures a fork and its original can both grow far
+// beyond the pages they share.
+func TestSparseForkGrowth(t *testing.T) {
+	dsu := New[int]()
+	for i := 0; i < 1000; i++ {
+		dsu.Union(0, i)
+	}
+	fork := dsu.Fork()
+	for i := 1000; i < 50_000; i++ {
+		dsu.Union(0, i)
+		fork.Union(-1, -i)
+	}
+
+	if len(dsu.Groups()) != 1 {
+		t.Fatalf("expected 1 group in the original, got %d", len(dsu.Groups()))
+	}
+	if len(fork.Groups()) != 2 {
+		t.Fatalf("expected 2 groups in the fork, got %d", len(fork.Groups()))
+	}
+	if fork.Connected(0, 1000) || !fork.Connected(0, 999) || !fork.Connected(-1, -49_999) {
+		t.Fatal("unexpected connectivity in the fork")
+	}
+}
+
+// TestSparseForkDeepChain ensures long chains of forks of forks remain
+// correct.
+func TestSparseForkDeepChain(t *testing.T) {
+	dsu := New[int]()
+	var forks []*DSU[int]
+	for i := 0; i < 24; i++ {
+		forks = append(forks, dsu)
+		dsu = dsu.Fork()
+		dsu.Union(i, i+1)
+	}
+
+	for i, f := range forks {
+		if i > 0 && !f.Connected(0, i) {
+			t.Fatalf("fork %d: expected 0 and %d to be connected", i, i)
+		}
+		if f.Connected(0, i+1) {
+			t.Fatalf("fork %d: expected 0 and %d to be disconnected", i, i+1)
+		}
+	}
+}
+
+// TestSparseUnionAll ensures UnionAll merges a whole group and counts merges.
+func TestSparseUnionAll(t *testing.T) {
+	dsu := New[string]()
+	dsu.Union("b", "c")
+
+	if merges := dsu.UnionAll("a", "b", "c", "d"); merges != 2 {
+		t.Fatalf("expected 2 merges, got %d", merges)
+	}
+	if !dsu.Connected("a", "d") {
+		t.Fatal("expected a and d to be connected")
+	}
+
+	dsu.UnionAll("solo")
+	if len(dsu.Groups()) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(dsu.Groups()))
+	}
+}
+
+// TestSparseUnionPairs ensures UnionPairs counts only actual merges.
+func TestSparseUnionPairs(t *testing.T) {
+	dsu := New[int]()
+	pairs := [][2]int{{10, 11}, {11, 12}, {10, 12}, {20, 21}}
+
+	if merges := dsu.UnionPairs(pairs); merges != 3 {
+		t.Fatalf("expected 3 merges, got %d", merges)
+	}
+	if len(dsu.Groups()) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(dsu.Groups()))
+	}
+}
+
+// TestSparseFindManyConnectedMany ensures the batch queries agree with
+// Find and Connected.
+func TestSparseFindManyConnectedMany(t *testing.T) {
+	dsu := New[int]()
+	dsu.UnionPairs([][2]int{{1, 2}, {2, 3}})
+
+	roots := dsu.FindMany([]int{0}, []int{1, 3, 7})
+	if len(roots) != 4 || roots[0] != 0 {
+		t.Fatalf("FindMany did not append to dst: %v", roots)
+	}
+	if roots[1] != dsu.Find(1) || roots[2] != dsu.Find(1) || roots[3] != 7 {
+		t.Fatalf("unexpected roots %v", roots)
+	}
+
+	got := dsu.ConnectedMany(nil, [][2]int{{1, 3}, {1, 7}})
+	if len(got) != 2 || !got[0] || got[1] {
+		t.Fatalf("unexpected ConnectedMany result %v", got)
+	}
+}
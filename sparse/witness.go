@@ -0,0 +1,122 @@
+package sparse
+
+import (
+	"github.com/arunksaha/gdsu"
+	"github.com/arunksaha/gdsu/internal/forest"
+)
+
+// Edge is a union recorded by a Witness: the arguments of a successful
+// Union and the label it was given.
+type Edge[T comparable, L any] struct {
+	X, Y  T
+	Label L
+}
+
+// Witness is a DSU that retains every successful union as an edge of a
+// spanning forest, so that it can explain why two elements are connected.
+//
+// The forest is stored with parent pointers. Union reroots the smaller of
+// the two trees at its endpoint of the new edge and hangs it below the
+// other endpoint, which takes O(log n) amortized time per union; Path
+// climbs from both elements in turn towards their lowest common ancestor,
+// in time linear in the length of the path.
+type Witness[T comparable, L any] struct {
+	dsu *DSU[T]
+
+	// size maps the root of every set of dsu with more than one element
+	// to its number of elements.
+	size map[T]int
+
+	// forest holds the recorded edges, each labeled with itself.
+	forest *forest.Map[T, Edge[T, L]]
+}
+
+// NewWitness creates an empty Witness.
+func NewWitness[T comparable, L any]() *Witness[T, L] {
+	return &Witness[T, L]{
+		dsu:    New[T](),
+		size:   make(map[T]int),
+		forest: forest.NewMap[T, Edge[T, L]](nil),
+	}
+}
+
+// sizeOf returns the number of elements of the set whose root is r.
+func (w *Witness[T, L]) sizeOf(r T) int {
+	if s, ok := w.size[r]; ok {
+		return s
+	}
+	return 1
+}
+
+// Find returns the representative element (root) of the set containing x.
+// If x is not present, it is added as a singleton set.
+func (w *Witness[T, L]) Find(x T) T {
+	return w.dsu.Find(x)
+}
+
+// Union merges the sets containing x and y, recording the union with the
+// zero label. Returns true if the sets were separate and are now merged.
+func (w *Witness[T, L]) Union(x, y T) bool {
+	var zero L
+	_, merged := w.UnionWith(x, y, zero)
+	return merged
+}
+
+// UnionWith merges the sets containing x and y and records the union with
+// the given label. Returns true if the sets were separate and are now
+// merged. Otherwise nothing is recorded, and the returned path is the one
+// Path(x, y) reports, which together with the rejected union forms a
+// cycle.
+func (w *Witness[T, L]) UnionWith(x, y T, label L) ([]Edge[T, L], bool) {
+	rootX, rootY := w.dsu.Find(x), w.dsu.Find(y)
+	if rootX == rootY {
+		path, _ := w.Path(x, y)
+		return path, false
+	}
+
+	// hang the smaller tree below the larger one
+	child, parent := x, y
+	if w.sizeOf(rootX) > w.sizeOf(rootY) {
+		child, parent = y, x
+	}
+	w.forest.Link(child, parent, Edge[T, L]{X: x, Y: y, Label: label})
+
+	total := w.sizeOf(rootX) + w.sizeOf(rootY)
+	delete(w.size, rootX)
+	delete(w.size, rootY)
+	w.dsu.Union(rootX, rootY)
+	w.size[w.dsu.Find(rootX)] = total
+	return nil, true
+}
+
+// Path returns the recorded unions on the forest path from x to y, in
+// order, and true; consecutive edges share an element, but each edge keeps
+// the orientation of its Union call. If x and y are not connected, Path
+// returns false. If x or y did not already exist, then singleton sets are
+// created for them.
+func (w *Witness[T, L]) Path(x, y T) ([]Edge[T, L], bool) {
+	if !w.dsu.Connected(x, y) {
+		return nil, false
+	}
+
+	return w.forest.Path(x, y), true
+}
+
+// Connected reports whether x and y are in the same set.
+// If x or y did not already exist, then singleton sets are created for them.
+func (w *Witness[T, L]) Connected(x, y T) bool {
+	return w.dsu.Connected(x, y)
+}
+
+// Groups returns a map from root -> slice of elements in that set.
+func (w *Witness[T, L]) Groups() map[T][]T {
+	return w.dsu.Groups()
+}
+
+// Edges returns every recorded union, in no particular order.
+func (w *Witness[T, L]) Edges() []Edge[T, L] {
+	return w.forest.Labels()
+}
+
+// Compile-time assertion that Witness implements gdsu.DSU.
+var _ gdsu.DSU[int] = (*Witness[int, string])(nil)
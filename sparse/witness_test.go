@@ -0,0 +1,79 @@
+package sparse
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkWalk ensures path is a walk from x to y along recorded edges that
+// uses no edge twice.
+func checkWalk(t *testing.T, recorded map[Edge[int, int]]bool, path []Edge[int, int], x, y int) {
+	t.Helper()
+	used := make(map[Edge[int, int]]bool)
+	at := x
+	for _, e := range path {
+		if !recorded[e] || used[e] {
+			t.Fatalf("path %v from %d to %d uses edge %v wrongly", path, x, y, e)
+		}
+		used[e] = true
+		switch at {
+		case e.X:
+			at = e.Y
+		case e.Y:
+			at = e.X
+		default:
+			t.Fatalf("path %v from %d to %d is not a walk at %v", path, x, y, e)
+		}
+	}
+	if at != y {
+		t.Fatalf("path %v from %d ends at %d, want %d", path, x, at, y)
+	}
+}
+
+// TestWitnessPaths performs random labeled unions and checks every path,
+// including the cycles reported for rejected unions.
+func TestWitnessPaths(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 200
+	w := NewWitness[int, int]()
+	ref := New[int]()
+	recorded := make(map[Edge[int, int]]bool)
+	for i := 0; i < 3*n; i++ {
+		x, y := rng.Intn(n), rng.Intn(n)
+		cycle, merged := w.UnionWith(x, y, i)
+		if merged != ref.Union(x, y) {
+			t.Fatalf("UnionWith(%d, %d) = %v, want %v", x, y, merged, !merged)
+		}
+		if merged {
+			recorded[Edge[int, int]{X: x, Y: y, Label: i}] = true
+		} else {
+			checkWalk(t, recorded, cycle, x, y)
+		}
+
+		a, b := rng.Intn(n), rng.Intn(n)
+		path, ok := w.Path(a, b)
+		if ok != ref.Connected(a, b) {
+			t.Fatalf("Path(%d, %d) reported %v, want %v", a, b, ok, !ok)
+		}
+		if ok {
+			checkWalk(t, recorded, path, a, b)
+		}
+	}
+	if len(w.Edges()) != len(recorded) {
+		t.Fatalf("expected %d edges, got %d", len(recorded), len(w.Edges()))
+	}
+}
+
+// TestWitnessInterface checks the gdsu.DSU methods.
+func TestWitnessInterface(t *testing.T) {
+	w := NewWitness[string, struct{}]()
+	if !w.Union("a", "b") || w.Union("b", "a") || !w.Connected("a", "b") || w.Connected("a", "c") {
+		t.Fatalf("unexpected connectivity")
+	}
+	if len(w.Groups()) != 2 || w.Find("b") != w.Find("a") {
+		t.Fatalf("unexpected groups %v", w.Groups())
+	}
+	if _, ok := w.Path("a", "d"); ok {
+		t.Fatalf("expected no path between a and d")
+	}
+}
@@ -0,0 +1,273 @@
+// Package static provides a Disjoint Set Union (DSU) for int elements
+// whose unions follow a union tree declared in advance, using the
+// linear-time algorithm of Gabow and Tarjan (1985).
+//
+// The union tree is given as a parent slice where parent[r] == r marks a
+// root. Every Union must join the two endpoints of a tree edge, so every
+// set is a connected subtree and is represented by its topmost node.
+//
+// The tree is partitioned into microsets of fewer than 64 nodes. Within a
+// microset, the ancestors of every node and the nodes already linked to
+// their parents are kept as bit masks, so a Find that stays inside a
+// microset is a single mask operation. Finds that leave a microset go
+// through a compact.DSU over the nodes that microsets hang from, which are
+// at most a 32nd of all nodes. A sequence of m operations on n elements
+// therefore takes O(m + n) time.
+package static
+
+import (
+	"math/bits"
+	"slices"
+
+	"github.com/arunksaha/gdsu"
+	"github.com/arunksaha/gdsu/compact"
+)
+
+// microBits is the maximum number of nodes in a microset, the width of the
+// bit masks. Microsets other than those at roots have at least half as many.
+const microBits = 64
+
+// DSU is an int-based Disjoint-Set Union over the fixed range [0, n) whose
+// unions are restricted to the edges of a union tree.
+type DSU struct {
+	// parent[x] is the parent of x in the union tree, or x if x is a root.
+	parent []int
+
+	// micro[x] is the microset containing x, and anc[x] has a bit set for
+	// every ancestor of x in that microset, including x itself. Within a
+	// microset, descendants have lower bits than their ancestors.
+	micro []int
+	anc   []uint64
+
+	// members[start[s]+i] is the node of microset s with bit i.
+	members []int
+	start   []int
+
+	// linked[s] has a bit set for every node of microset s that has been
+	// united with its parent.
+	linked []uint64
+
+	// hang[s] is the parent of the topmost nodes of microset s, which lies
+	// outside it, or -1 if they are roots.
+	hang []int
+
+	// macro unites the hang nodes, numbered by macroID, along the paths
+	// through which sets span several microsets; top[r] is the topmost
+	// node of the macro set whose root is r.
+	macro   *compact.DSU
+	macroID []int
+	top     []int
+}
+
+// New creates a DSU of singleton sets for the elements [0, len(parent))
+// with the union tree given by parent: parent[x] is the parent of x, or x
+// itself if x is a root.
+// Panics if any parent is out of range or the parents contain a cycle.
+func New(parent []int) *DSU {
+	n := len(parent)
+	for _, p := range parent {
+		if p < 0 || p >= n {
+			panic("static.DSU: parent out of range in New")
+		}
+	}
+
+	// children in compressed sparse row form
+	offsets := make([]int, n+1)
+	for x, p := range parent {
+		if p != x {
+			offsets[p+1]++
+		}
+	}
+	for x := 0; x < n; x++ {
+		offsets[x+1] += offsets[x]
+	}
+	children := make([]int, offsets[n])
+	next := append([]int(nil), offsets[:n]...)
+	for x, p := range parent {
+		if p != x {
+			children[next[p]] = x
+			next[p]++
+		}
+	}
+
+	// preorder of the forest; nodes on a cycle are never reached
+	order := make([]int, 0, n)
+	for x, p := range parent {
+		if p == x {
+			order = append(order, x)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		order = append(order, children[offsets[order[i]]:offsets[order[i]+1]]...)
+	}
+	if len(order) != n {
+		panic("static.DSU: union tree has a cycle in New")
+	}
+
+	dsu := &DSU{
+		parent:  slices.Clone(parent),
+		micro:   make([]int, n),
+		anc:     make([]uint64, n),
+		members: make([]int, 0, n),
+		macroID: make([]int, n),
+	}
+	for x := range dsu.macroID {
+		dsu.macroID[x] = -1
+	}
+
+	// Partition bottom-up. Every node passes a pending list of fewer than
+	// microBits/2 nodes, itself included, to its parent, chained through
+	// next with descendants before ancestors. A parent gathers the pending
+	// lists of its children and closes them as a microset hanging from
+	// itself whenever they reach microBits/2 nodes.
+	head, tail, size := make([]int, n), make([]int, n), make([]int, n)
+	for i := n - 1; i >= 0; i-- {
+		v := order[i]
+		gHead, gTail, gSize := -1, -1, 0
+		for _, c := range children[offsets[v]:offsets[v+1]] {
+			if size[c] == 0 {
+				continue
+			}
+			if gHead < 0 {
+				gHead = head[c]
+			} else {
+				next[gTail] = head[c]
+			}
+			gTail, gSize = tail[c], gSize+size[c]
+			if gSize >= microBits/2 {
+				dsu.close(next, gHead, v)
+				gHead, gTail, gSize = -1, -1, 0
+			}
+		}
+		if gHead < 0 {
+			gHead = v
+		} else {
+			next[gTail] = v
+		}
+		next[v], gTail, gSize = -1, v, gSize+1
+
+		switch {
+		case parent[v] == v:
+			dsu.close(next, gHead, -1)
+		case gSize >= microBits/2:
+			dsu.close(next, gHead, parent[v])
+		default:
+			head[v], tail[v], size[v] = gHead, gTail, gSize
+		}
+	}
+
+	numMacro := 0
+	for _, h := range dsu.hang {
+		if h >= 0 && dsu.macroID[h] < 0 {
+			dsu.macroID[h] = numMacro
+			numMacro++
+		}
+	}
+	dsu.macro = compact.New(numMacro)
+	dsu.top = make([]int, numMacro)
+	for x, id := range dsu.macroID {
+		if id >= 0 {
+			dsu.top[id] = x
+		}
+	}
+	return dsu
+}
+
+// close makes the list starting at first and chained through next a
+// microset hanging from hang.
+func (dsu *DSU) close(next []int, first, hang int) {
+	s := len(dsu.hang)
+	dsu.hang = append(dsu.hang, hang)
+	dsu.linked = append(dsu.linked, 0)
+	dsu.start = append(dsu.start, len(dsu.members))
+	for x := first; x >= 0; x = next[x] {
+		dsu.micro[x] = s
+		dsu.members = append(dsu.members, x)
+	}
+	// ancestors have higher bits, so visit them first
+	ms := dsu.members[dsu.start[s]:]
+	for i := len(ms) - 1; i >= 0; i-- {
+		x := ms[i]
+		dsu.anc[x] = 1 << i
+		if p := dsu.parent[x]; p != x && p != hang {
+			dsu.anc[x] |= dsu.anc[p]
+		}
+	}
+}
+
+// boundsCheck ensures x is within [0, n).
+func (dsu *DSU) boundsCheck(x int) bool {
+	return 0 <= x && x < len(dsu.parent)
+}
+
+// Find returns the representative element of the set containing x, which
+// is its topmost node in the union tree.
+// Panics if x is out of range.
+func (dsu *DSU) Find(x int) int {
+	if !dsu.boundsCheck(x) {
+		panic("static.DSU: index out of range in Find")
+	}
+	for {
+		s := dsu.micro[x]
+		if c := dsu.anc[x] &^ dsu.linked[s]; c != 0 {
+			return dsu.members[dsu.start[s]+bits.TrailingZeros64(c)]
+		}
+		// the set of x extends beyond its microset to the hang node
+		h := dsu.macroID[dsu.hang[s]]
+		if id := dsu.macroID[x]; id >= 0 {
+			// x is itself a hang node; remember that the macro set of x
+			// continues into the one of h
+			t := dsu.top[dsu.macro.Find(h)]
+			dsu.macro.Union(id, h)
+			dsu.top[dsu.macro.Find(id)] = t
+		}
+		x = dsu.top[dsu.macro.Find(h)]
+	}
+}
+
+// Union merges the sets containing x and y, which must be the endpoints of
+// an edge of the union tree, in either order.
+// Returns true if the sets were separate and are now merged.
+// Panics if x or y are out of range or not joined by a union-tree edge.
+func (dsu *DSU) Union(x, y int) bool {
+	if !dsu.boundsCheck(x) || !dsu.boundsCheck(y) {
+		panic("static.DSU: index out of range in Union")
+	}
+	child := x
+	switch {
+	case dsu.parent[x] == y && x != y:
+	case dsu.parent[y] == x && x != y:
+		child = y
+	default:
+		panic("static.DSU: not a union-tree edge in Union")
+	}
+	s := dsu.micro[child]
+	bit := dsu.anc[child] &^ (dsu.anc[child] - 1)
+	if dsu.linked[s]&bit != 0 {
+		return false
+	}
+	dsu.linked[s] |= bit
+	return true
+}
+
+// Connected reports whether x and y are in the same set.
+// Panics if x or y are out of range.
+func (dsu *DSU) Connected(x, y int) bool {
+	if !dsu.boundsCheck(x) || !dsu.boundsCheck(y) {
+		panic("static.DSU: index out of range in Connected")
+	}
+	return dsu.Find(x) == dsu.Find(y)
+}
+
+// Groups returns a map from root -> slice of elements in that set.
+func (dsu *DSU) Groups() map[int][]int {
+	groups := make(map[int][]int)
+	for x := range dsu.parent {
+		root := dsu.Find(x)
+		groups[root] = append(groups[root], x)
+	}
+	return groups
+}
+
+// Compile-time assertion that DSU implements gdsu.DSU[int].
+var _ gdsu.DSU[int] = (*DSU)(nil)
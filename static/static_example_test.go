@@ -0,0 +1,22 @@
+package static
+
+import "fmt"
+
+// Example declares a union tree up front and then merges along its edges.
+func Example() {
+	//      0
+	//     / \
+	//    1   2
+	//   / \
+	//  3   4
+	dsu := New([]int{0, 0, 0, 1, 1})
+
+	dsu.Union(3, 1)
+	dsu.Union(1, 0)
+	fmt.Println(dsu.Find(3), dsu.Find(4), dsu.Find(2))
+	fmt.Println(dsu.Connected(3, 0))
+
+	// Output:
+	// 0 4 2
+	// true
+}
@@ -0,0 +1,150 @@
+package static
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/arunksaha/gdsu"
+)
+
+// naiveFind climbs from x while the edge to the parent has been united.
+func naiveFind(parent []int, linked []bool, x int) int {
+	for linked[x] {
+		x = parent[x]
+	}
+	return x
+}
+
+// checkRandom unites the tree edges of parent in random order, checking
+// every element against naiveFind along the way.
+func checkRandom(t *testing.T, rng *rand.Rand, parent []int) {
+	t.Helper()
+	n := len(parent)
+	dsu := New(parent)
+	linked := make([]bool, n)
+	perm := rng.Perm(n)
+	for i, x := range perm {
+		if parent[x] != x {
+			merged := dsu.Union(parent[x], x)
+			if !merged {
+				t.Fatalf("Union(%d, %d) reported no merge", parent[x], x)
+			}
+			linked[x] = true
+			if dsu.Union(x, parent[x]) {
+				t.Fatalf("repeated Union(%d, %d) reported a merge", x, parent[x])
+			}
+		}
+		if i%(n/20+1) == 0 || i == n-1 {
+			for y := 0; y < n; y++ {
+				if got, want := dsu.Find(y), naiveFind(parent, linked, y); got != want {
+					t.Fatalf("Find(%d) = %d, want %d", y, got, want)
+				}
+			}
+		}
+	}
+}
+
+// TestStaticMatchesNaive cross-checks random forests of various shapes.
+func TestStaticMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 30; trial++ {
+		n := 1 + rng.Intn(3000)
+		parent := make([]int, n)
+		for x := range parent {
+			switch {
+			case x == 0 || rng.Intn(200) == 0:
+				parent[x] = x // a new tree
+			case trial%3 == 0:
+				parent[x] = x - 1 - rng.Intn(min(x, 3)) // long paths
+			case trial%3 == 1:
+				parent[x] = rng.Intn(min(x, 5)) // wide stars
+			default:
+				parent[x] = rng.Intn(x)
+			}
+		}
+		// relabel so that parents do not precede their children
+		perm := rng.Perm(n)
+		shuffled := make([]int, n)
+		for x, p := range parent {
+			shuffled[perm[x]] = perm[p]
+		}
+		checkRandom(t, rng, shuffled)
+	}
+}
+
+// TestStaticDeepPath unites a long path bottom-up and top-down.
+func TestStaticDeepPath(t *testing.T) {
+	const n = 100_000
+	parent := make([]int, n)
+	for x := 1; x < n; x++ {
+		parent[x] = x - 1
+	}
+
+	up := New(parent)
+	for x := n - 1; x > 0; x-- {
+		up.Union(x, x-1)
+	}
+	if up.Find(n-1) != 0 || len(up.Groups()) != 1 {
+		t.Fatalf("expected a single set rooted at 0")
+	}
+
+	down := New(parent)
+	for x := 1; x < n; x++ {
+		down.Union(x-1, x)
+		if x%1000 == 0 && down.Find(x) != 0 {
+			t.Fatalf("Find(%d) = %d, want 0", x, down.Find(x))
+		}
+	}
+	if !down.Connected(0, n-1) {
+		t.Fatalf("expected 0 and %d to be connected", n-1)
+	}
+}
+
+// TestStaticGroups checks that sets are keyed by their topmost node.
+func TestStaticGroups(t *testing.T) {
+	//     0       4
+	//    / \      |
+	//   1   2     5
+	//   |
+	//   3
+	dsu := New([]int{0, 0, 0, 1, 4, 4})
+	dsu.Union(3, 1)
+	dsu.Union(1, 0)
+	dsu.Union(4, 5)
+
+	groups := dsu.Groups()
+	if len(groups) != 3 || len(groups[0]) != 3 || len(groups[2]) != 1 || len(groups[4]) != 2 {
+		t.Fatalf("unexpected groups %v", groups)
+	}
+	if dsu.Connected(2, 3) || !dsu.Connected(0, 3) {
+		t.Fatalf("unexpected connectivity")
+	}
+}
+
+// TestStaticPanics ensures invalid trees and operations panic.
+func TestStaticPanics(t *testing.T) {
+	expectPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("expected panic in %s, got none", name)
+			}
+		}()
+		fn()
+	}
+
+	expectPanic("New out of range", func() { New([]int{0, 2}) })
+	expectPanic("New cycle", func() { New([]int{0, 2, 1}) })
+
+	dsu := New([]int{0, 0, 1})
+	expectPanic("Find", func() { dsu.Find(3) })
+	expectPanic("Union", func() { dsu.Union(-1, 0) })
+	expectPanic("Connected", func() { dsu.Connected(0, 3) })
+	expectPanic("Union non-edge", func() { dsu.Union(0, 2) })
+	expectPanic("Union root", func() { dsu.Union(0, 0) })
+}
+
+// TestStaticImplementsInterface ensures DSU satisfies gdsu.DSU[int].
+func TestStaticImplementsInterface(t *testing.T) {
+	var _ gdsu.DSU[int] = New(nil)
+}
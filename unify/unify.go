@@ -0,0 +1,398 @@
+// Package unify implements first-order unification of type terms for
+// Hindley–Milner type inference.
+//
+// Type variables are the elements of a sparse.DSU: unifying two variables
+// unites their sets, and the representative of every set carries the term
+// the variables are bound to, if any. Unify is transactional: it first
+// unifies on a private overlay of the DSU and commits the overlay only if
+// unification succeeds, so a failed Unify leaves no trace. Longer
+// speculative sequences can be undone with Snapshot and Rollback, which
+// fork the DSU.
+package unify
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/arunksaha/gdsu/sparse"
+)
+
+// Type is a type term: a Var, a Con or a Func.
+type Type interface {
+	fmt.Stringer
+	isType()
+}
+
+// Var is a type variable.
+type Var int
+
+// Con is a type constructor applied to arguments, such as int (with no
+// arguments) or List[t0].
+type Con struct {
+	Name string
+	Args []Type
+}
+
+// Func is a function type.
+type Func struct {
+	Params []Type
+	Result Type
+}
+
+func (Var) isType()  {}
+func (Con) isType()  {}
+func (Func) isType() {}
+
+// String returns the variable as t followed by its number.
+func (v Var) String() string {
+	return fmt.Sprintf("t%d", int(v))
+}
+
+// String returns the constructor with its arguments in brackets.
+func (c Con) String() string {
+	if len(c.Args) == 0 {
+		return c.Name
+	}
+	return c.Name + "[" + join(c.Args) + "]"
+}
+
+// String returns the function type as (params) -> result.
+func (f Func) String() string {
+	return "(" + join(f.Params) + ") -> " + f.Result.String()
+}
+
+// join formats ts separated by commas.
+func join(ts []Type) string {
+	s := make([]string, len(ts))
+	for i, t := range ts {
+		s[i] = t.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+// MismatchError reports that two types have different shapes: different
+// kinds of terms, constructors, or numbers of arguments or parameters.
+type MismatchError struct {
+	// A and B are the types passed to Unify, and Left and Right the
+	// subterms of them that do not match, resolved as far as possible.
+	A, B        Type
+	Left, Right Type
+}
+
+func (e *MismatchError) Error() string {
+	msg := fmt.Sprintf("unify: cannot unify %v with %v", e.Left, e.Right)
+	if e.Left.String() != e.A.String() || e.Right.String() != e.B.String() {
+		msg += fmt.Sprintf(" while unifying %v with %v", e.A, e.B)
+	}
+	return msg
+}
+
+// OccursError reports that unification would bind a variable to a term
+// containing it, which would make the type infinite.
+type OccursError struct {
+	// A and B are the types passed to Unify, resolved as far as possible.
+	A, B Type
+	Var  Var
+	Type Type
+}
+
+func (e *OccursError) Error() string {
+	return fmt.Sprintf("unify: infinite type: %v occurs in %v while unifying %v with %v", e.Var, e.Type, e.A, e.B)
+}
+
+// Unifier holds the bindings of type variables.
+type Unifier struct {
+	dsu *sparse.DSU[Var]
+
+	// bound maps the representative of every bound set of variables to
+	// its term. Entries are only ever added; trail lists their keys in
+	// order, so that Rollback can remove the newer ones.
+	bound map[Var]Type
+	trail []Var
+
+	next Var
+}
+
+// New creates a Unifier with no variables.
+func New() *Unifier {
+	return &Unifier{dsu: sparse.New[Var](), bound: make(map[Var]Type)}
+}
+
+// NewVar returns a fresh type variable.
+func (u *Unifier) NewVar() Var {
+	v := u.next
+	u.next++
+	return v
+}
+
+// overlay is a tentative extension of the Unifier's bindings made during a
+// single Unify.
+type overlay struct {
+	u *Unifier
+
+	// link maps representatives of the Unifier to the representatives they
+	// are tentatively united with, in order of linking.
+	link  map[Var]Var
+	order []Var
+
+	// bind maps tentative representatives to their tentative terms.
+	bind map[Var]Type
+}
+
+// find returns the tentative representative of v.
+func (o *overlay) find(v Var) Var {
+	r := o.u.dsu.Find(v)
+	for {
+		next, ok := o.link[r]
+		if !ok {
+			return r
+		}
+		r = next
+	}
+}
+
+// shallow returns t with a bound variable at its top replaced by its term,
+// and a free variable replaced by its representative.
+func (o *overlay) shallow(t Type) Type {
+	v, ok := t.(Var)
+	if !ok {
+		return t
+	}
+	r := o.find(v)
+	if b, ok := o.bind[r]; ok {
+		return b
+	}
+	if b, ok := o.u.bound[r]; ok {
+		return b
+	}
+	return r
+}
+
+// resolve returns t with every bound variable replaced by its term.
+func (o *overlay) resolve(t Type) Type {
+	switch t := o.shallow(t).(type) {
+	case Con:
+		args := make([]Type, len(t.Args))
+		for i, a := range t.Args {
+			args[i] = o.resolve(a)
+		}
+		return Con{Name: t.Name, Args: args}
+	case Func:
+		params := make([]Type, len(t.Params))
+		for i, p := range t.Params {
+			params[i] = o.resolve(p)
+		}
+		return Func{Params: params, Result: o.resolve(t.Result)}
+	default:
+		return t
+	}
+}
+
+// occurs reports whether the free variable v occurs in t.
+func (o *overlay) occurs(v Var, t Type) bool {
+	switch t := o.shallow(t).(type) {
+	case Var:
+		return t == v
+	case Con:
+		return slices.ContainsFunc(t.Args, func(a Type) bool { return o.occurs(v, a) })
+	case Func:
+		return o.occurs(v, t.Result) || slices.ContainsFunc(t.Params, func(p Type) bool { return o.occurs(v, p) })
+	}
+	return false
+}
+
+// unify tentatively unifies a and b, returning the mismatching subterms
+// or the variable that fails the occurs check.
+func (o *overlay) unify(a, b Type) (left, right Type, occurs bool) {
+	a, b = o.shallow(a), o.shallow(b)
+	if va, ok := a.(Var); ok {
+		if vb, ok := b.(Var); ok {
+			if va != vb {
+				o.link[va] = vb
+				o.order = append(o.order, va)
+			}
+			return nil, nil, false
+		}
+		if o.occurs(va, b) {
+			return va, b, true
+		}
+		o.bind[va] = b
+		return nil, nil, false
+	}
+	if _, ok := b.(Var); ok {
+		return o.unify(b, a)
+	}
+
+	switch a := a.(type) {
+	case Con:
+		if b, ok := b.(Con); ok && a.Name == b.Name && len(a.Args) == len(b.Args) {
+			for i := range a.Args {
+				if l, r, occ := o.unify(a.Args[i], b.Args[i]); l != nil {
+					return l, r, occ
+				}
+			}
+			return nil, nil, false
+		}
+	case Func:
+		if b, ok := b.(Func); ok && len(a.Params) == len(b.Params) {
+			for i := range a.Params {
+				if l, r, occ := o.unify(a.Params[i], b.Params[i]); l != nil {
+					return l, r, occ
+				}
+			}
+			return o.unify(a.Result, b.Result)
+		}
+	}
+	return a, b, false
+}
+
+// Unify makes a and b equal by binding type variables. If that is not
+// possible, it returns a *MismatchError or an *OccursError and leaves the
+// bindings unchanged.
+func (u *Unifier) Unify(a, b Type) error {
+	o := &overlay{u: u, link: make(map[Var]Var), bind: make(map[Var]Type)}
+	left, right, occurs := o.unify(a, b)
+	if left != nil {
+		if occurs {
+			return &OccursError{A: o.resolve(a), B: o.resolve(b), Var: left.(Var), Type: o.resolve(right)}
+		}
+		return &MismatchError{A: o.resolve(a), B: o.resolve(b), Left: o.resolve(left), Right: o.resolve(right)}
+	}
+
+	// commit; linked variables are unbound, so only the tentative terms
+	// need to move to the new representatives
+	for _, v := range o.order {
+		u.dsu.Union(v, o.link[v])
+	}
+	for v, t := range o.bind {
+		r := u.dsu.Find(v)
+		u.bound[r] = t
+		u.trail = append(u.trail, r)
+	}
+	return nil
+}
+
+// Resolve returns t with every bound type variable replaced by its term
+// and every free one by the representative of its set.
+func (u *Unifier) Resolve(t Type) Type {
+	o := overlay{u: u}
+	return o.resolve(t)
+}
+
+// Scheme is a type scheme: a type quantified over some of its variables.
+type Scheme struct {
+	Vars []Var
+	Type Type
+}
+
+// String returns the scheme as forall vars. type.
+func (s Scheme) String() string {
+	if len(s.Vars) == 0 {
+		return s.Type.String()
+	}
+	vars := make([]string, len(s.Vars))
+	for i, v := range s.Vars {
+		vars[i] = v.String()
+	}
+	return "forall " + strings.Join(vars, " ") + ". " + s.Type.String()
+}
+
+// Generalize resolves t and quantifies it over its free variables, except
+// those that also occur free in env, the types of the enclosing
+// environment. The variables are listed in order of first occurrence.
+func (u *Unifier) Generalize(t Type, env ...Type) Scheme {
+	fixed := make(map[Var]bool)
+	for _, e := range env {
+		for _, v := range freeVars(u.Resolve(e), nil) {
+			fixed[v] = true
+		}
+	}
+	t = u.Resolve(t)
+	var vars []Var
+	for _, v := range freeVars(t, nil) {
+		if !fixed[v] {
+			vars = append(vars, v)
+		}
+	}
+	return Scheme{Vars: vars, Type: t}
+}
+
+// freeVars appends the variables of the resolved type t to vars in order
+// of first occurrence.
+func freeVars(t Type, vars []Var) []Var {
+	switch t := t.(type) {
+	case Var:
+		if !slices.Contains(vars, t) {
+			vars = append(vars, t)
+		}
+	case Con:
+		for _, a := range t.Args {
+			vars = freeVars(a, vars)
+		}
+	case Func:
+		for _, p := range t.Params {
+			vars = freeVars(p, vars)
+		}
+		vars = freeVars(t.Result, vars)
+	}
+	return vars
+}
+
+// Instantiate returns the type of s with every quantified variable
+// replaced by a fresh one.
+func (u *Unifier) Instantiate(s Scheme) Type {
+	fresh := make(map[Var]Type, len(s.Vars))
+	for _, v := range s.Vars {
+		fresh[v] = u.NewVar()
+	}
+	return substitute(s.Type, fresh)
+}
+
+// substitute returns t with the variables in sub replaced.
+func substitute(t Type, sub map[Var]Type) Type {
+	switch t := t.(type) {
+	case Var:
+		if s, ok := sub[t]; ok {
+			return s
+		}
+		return t
+	case Con:
+		args := make([]Type, len(t.Args))
+		for i, a := range t.Args {
+			args[i] = substitute(a, sub)
+		}
+		return Con{Name: t.Name, Args: args}
+	case Func:
+		params := make([]Type, len(t.Params))
+		for i, p := range t.Params {
+			params[i] = substitute(p, sub)
+		}
+		return Func{Params: params, Result: substitute(t.Result, sub)}
+	}
+	return t
+}
+
+// Snapshot records the current bindings for a later Rollback.
+type Snapshot struct {
+	dsu   *sparse.DSU[Var]
+	trail int
+}
+
+// Snapshot returns a snapshot of the current bindings. Taking a snapshot
+// forks the DSU, which is cheap.
+func (u *Unifier) Snapshot() Snapshot {
+	return Snapshot{dsu: u.dsu.Fork(), trail: len(u.trail)}
+}
+
+// Rollback restores the bindings recorded by s, undoing every Unify since.
+// Fresh variables are not reused. A snapshot may be rolled back to any
+// number of times, as long as no older snapshot has been rolled back to
+// in between.
+func (u *Unifier) Rollback(s Snapshot) {
+	u.dsu = s.dsu.Fork()
+	for _, v := range u.trail[s.trail:] {
+		delete(u.bound, v)
+	}
+	u.trail = u.trail[:s.trail]
+}
@@ -0,0 +1,25 @@
+package unify
+
+import "fmt"
+
+// Example infers the element type of a list and reports a type error.
+func Example() {
+	u := New()
+	intT := Con{Name: "int"}
+	elem := u.NewVar()
+
+	// head : List[elem] -> elem, applied to a List[int]
+	head := Func{Params: []Type{Con{Name: "List", Args: []Type{elem}}}, Result: elem}
+	result := u.NewVar()
+	if err := u.Unify(head, Func{Params: []Type{Con{Name: "List", Args: []Type{intT}}}, Result: result}); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(u.Resolve(result))
+
+	err := u.Unify(result, Con{Name: "string"})
+	fmt.Println(err)
+
+	// Output:
+	// int
+	// unify: cannot unify int with string
+}
@@ -0,0 +1,163 @@
+package unify
+
+import (
+	"errors"
+	"testing"
+)
+
+var (
+	intT  = Con{Name: "int"}
+	boolT = Con{Name: "bool"}
+)
+
+func list(t Type) Type { return Con{Name: "List", Args: []Type{t}} }
+
+// TestUnifyBinds checks that unification binds variables through nested
+// terms and chains of variables.
+func TestUnifyBinds(t *testing.T) {
+	u := New()
+	a, b, c := u.NewVar(), u.NewVar(), u.NewVar()
+
+	if err := u.Unify(a, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Unify(Func{Params: []Type{b}, Result: list(c)}, Func{Params: []Type{intT}, Result: list(boolT)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := u.Resolve(Func{Params: []Type{a}, Result: c}).String(); got != "(int) -> bool" {
+		t.Fatalf("Resolve = %s, want (int) -> bool", got)
+	}
+	if err := u.Unify(a, intT); err != nil {
+		t.Fatalf("unifying a bound variable with its term: %v", err)
+	}
+	if u.Resolve(a).String() != "int" {
+		t.Fatalf("Resolve(a) = %v, want int", u.Resolve(a))
+	}
+}
+
+// TestUnifyFreeVariables checks that free variables resolve to a shared
+// representative.
+func TestUnifyFreeVariables(t *testing.T) {
+	u := New()
+	a, b, c := u.NewVar(), u.NewVar(), u.NewVar()
+	if err := u.Unify(list(a), list(b)); err != nil {
+		t.Fatal(err)
+	}
+	if u.Resolve(a) != u.Resolve(b) || u.Resolve(a) == u.Resolve(c) {
+		t.Fatalf("unexpected representatives %v %v %v", u.Resolve(a), u.Resolve(b), u.Resolve(c))
+	}
+}
+
+// TestUnifyMismatch checks mismatch errors and that a failed Unify leaves
+// no partial bindings.
+func TestUnifyMismatch(t *testing.T) {
+	u := New()
+	a := u.NewVar()
+	pair := func(x, y Type) Type { return Con{Name: "Pair", Args: []Type{x, y}} }
+
+	err := u.Unify(pair(a, intT), pair(boolT, boolT))
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected a MismatchError, got %v", err)
+	}
+	want := "unify: cannot unify int with bool while unifying Pair[bool, int] with Pair[bool, bool]"
+	if err.Error() != want {
+		t.Fatalf("error %q, want %q", err, want)
+	}
+	if u.Resolve(a) != a {
+		t.Fatalf("failed Unify bound %v to %v", a, u.Resolve(a))
+	}
+
+	for _, tc := range []struct{ x, y Type }{
+		{intT, boolT},
+		{list(intT), Con{Name: "List"}},
+		{Func{Params: []Type{intT}, Result: intT}, Func{Result: intT}},
+		{Func{Result: intT}, intT},
+	} {
+		if err := u.Unify(tc.x, tc.y); !errors.As(err, &mismatch) {
+			t.Fatalf("Unify(%v, %v) = %v, want a MismatchError", tc.x, tc.y, err)
+		}
+	}
+	if err := u.Unify(intT, boolT); err.Error() != "unify: cannot unify int with bool" {
+		t.Fatalf("unexpected error %q", err)
+	}
+}
+
+// TestUnifyOccursCheck checks that infinite types are rejected.
+func TestUnifyOccursCheck(t *testing.T) {
+	u := New()
+	a, b := u.NewVar(), u.NewVar()
+	if err := u.Unify(a, b); err != nil {
+		t.Fatal(err)
+	}
+	err := u.Unify(a, Func{Params: []Type{b}, Result: intT})
+	var occurs *OccursError
+	if !errors.As(err, &occurs) {
+		t.Fatalf("expected an OccursError, got %v", err)
+	}
+	if u.Resolve(occurs.Var) != u.Resolve(a) {
+		t.Fatalf("unexpected variable %v", occurs.Var)
+	}
+	if err := u.Unify(list(a), list(list(b))); !errors.As(err, &occurs) {
+		t.Fatalf("expected an OccursError, got %v", err)
+	}
+}
+
+// TestSnapshotRollback undoes a sequence of unifications.
+func TestSnapshotRollback(t *testing.T) {
+	u := New()
+	a, b, c := u.NewVar(), u.NewVar(), u.NewVar()
+	if err := u.Unify(a, intT); err != nil {
+		t.Fatal(err)
+	}
+
+	s := u.Snapshot()
+	for i := 0; i < 2; i++ {
+		if err := u.Unify(b, c); err != nil {
+			t.Fatal(err)
+		}
+		if err := u.Unify(c, boolT); err != nil {
+			t.Fatal(err)
+		}
+		if u.Resolve(b).String() != "bool" {
+			t.Fatalf("Resolve(b) = %v, want bool", u.Resolve(b))
+		}
+		u.Rollback(s)
+		if u.Resolve(b) != b || u.Resolve(c) != c || u.Resolve(a).String() != "int" {
+			t.Fatalf("rollback left %v %v %v", u.Resolve(a), u.Resolve(b), u.Resolve(c))
+		}
+	}
+	if d := u.NewVar(); d <= c {
+		t.Fatalf("fresh variable %v reused after rollback", d)
+	}
+}
+
+// TestLetPolymorphism infers let id = fun x -> x in (id 1, id true).
+func TestLetPolymorphism(t *testing.T) {
+	u := New()
+	x := u.NewVar()
+	id := u.Generalize(Func{Params: []Type{x}, Result: x})
+	if id.String() != "forall t0. (t0) -> t0" {
+		t.Fatalf("Generalize = %v", id)
+	}
+
+	for _, arg := range []Type{intT, boolT} {
+		result := u.NewVar()
+		if err := u.Unify(u.Instantiate(id), Func{Params: []Type{arg}, Result: result}); err != nil {
+			t.Fatal(err)
+		}
+		if u.Resolve(result).String() != arg.String() {
+			t.Fatalf("id applied to %v returned %v", arg, u.Resolve(result))
+		}
+	}
+
+	// variables free in the environment stay monomorphic
+	y, z := u.NewVar(), u.NewVar()
+	s := u.Generalize(Func{Params: []Type{y}, Result: z}, list(y))
+	if len(s.Vars) != 1 || s.Vars[0] != u.Resolve(z) {
+		t.Fatalf("Generalize = %v, want only %v quantified", s, z)
+	}
+	if got := u.Generalize(intT).String(); got != "int" {
+		t.Fatalf("Generalize(int) = %s", got)
+	}
+}